@@ -0,0 +1,47 @@
+package apikey
+
+import (
+	"strings"
+	"time"
+
+	"footballteam/user"
+)
+
+// APIKey untuk integrasi mesin-ke-mesin. Key lengkap hanya ditampilkan sekali
+// saat dibuat; yang disimpan hanya Prefix (untuk pencarian) dan hash-nya.
+type APIKey struct {
+	ID          int    `gorm:"primaryKey;autoIncrement"`
+	Name        string `gorm:"size:100;not null"`
+	Prefix      string `gorm:"size:16;uniqueIndex;not null"`
+	KeyHash     string `gorm:"size:64;not null"`
+	Scopes      string `gorm:"size:255"` // dipisah koma, berisi user.Permission
+	ExpiresAt   *time.Time
+	LastUsedAt  *time.Time
+	RevokedAt   *time.Time
+	CreatedByID int `gorm:"not null"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (k APIKey) ScopeList() []string {
+	if k.Scopes == "" {
+		return []string{}
+	}
+	return strings.Split(k.Scopes, ",")
+}
+
+func (k APIKey) HasScope(permission user.Permission) bool {
+	for _, scope := range k.ScopeList() {
+		if scope == string(permission) {
+			return true
+		}
+	}
+	return false
+}
+
+func (k APIKey) IsActive(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || k.ExpiresAt.After(now)
+}
@@ -0,0 +1,40 @@
+package apikey
+
+import "time"
+
+type APIKeyFormatter struct {
+	ID          int        `json:"id"`
+	Name        string     `json:"name"`
+	Prefix      string     `json:"prefix"`
+	Scopes      []string   `json:"scopes"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	RevokedAt   *time.Time `json:"revoked_at"`
+	Active      bool       `json:"active"`
+	CreatedByID int        `json:"created_by_id"`
+	CreatedAt   time.Time  `json:"created_at"`
+	Key         string     `json:"key,omitempty"` // hanya terisi saat key baru dibuat
+}
+
+func FormatAPIKey(key APIKey) APIKeyFormatter {
+	return APIKeyFormatter{
+		ID:          key.ID,
+		Name:        key.Name,
+		Prefix:      key.Prefix,
+		Scopes:      key.ScopeList(),
+		ExpiresAt:   key.ExpiresAt,
+		LastUsedAt:  key.LastUsedAt,
+		RevokedAt:   key.RevokedAt,
+		Active:      key.IsActive(time.Now()),
+		CreatedByID: key.CreatedByID,
+		CreatedAt:   key.CreatedAt,
+	}
+}
+
+func FormatAPIKeys(keys []APIKey) []APIKeyFormatter {
+	formatted := []APIKeyFormatter{}
+	for _, k := range keys {
+		formatted = append(formatted, FormatAPIKey(k))
+	}
+	return formatted
+}
@@ -0,0 +1,14 @@
+package apikey
+
+import (
+	"time"
+
+	"footballteam/user"
+)
+
+type CreateAPIKeyInput struct {
+	Name      string     `json:"name" binding:"required"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	User      user.User  `json:"-"`
+}
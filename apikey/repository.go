@@ -0,0 +1,56 @@
+package apikey
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	FindAll() ([]APIKey, error)
+	FindByID(id int) (APIKey, error)
+	FindByPrefix(prefix string) (APIKey, error)
+	Create(key APIKey) (APIKey, error)
+	Update(key APIKey) (APIKey, error)
+	TouchLastUsed(id int, usedAt time.Time) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *repository {
+	return &repository{db}
+}
+
+func (r *repository) FindAll() ([]APIKey, error) {
+	var keys []APIKey
+	err := r.db.Order("id").Find(&keys).Error
+	return keys, err
+}
+
+func (r *repository) FindByID(id int) (APIKey, error) {
+	var key APIKey
+	err := r.db.First(&key, id).Error
+	return key, err
+}
+
+func (r *repository) FindByPrefix(prefix string) (APIKey, error) {
+	var key APIKey
+	err := r.db.Where("prefix = ?", prefix).First(&key).Error
+	return key, err
+}
+
+func (r *repository) Create(key APIKey) (APIKey, error) {
+	err := r.db.Create(&key).Error
+	return key, err
+}
+
+func (r *repository) Update(key APIKey) (APIKey, error) {
+	err := r.db.Save(&key).Error
+	return key, err
+}
+
+func (r *repository) TouchLastUsed(id int, usedAt time.Time) error {
+	return r.db.Model(&APIKey{}).Where("id = ?", id).UpdateColumn("last_used_at", usedAt).Error
+}
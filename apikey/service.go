@@ -0,0 +1,152 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"footballteam/helper"
+	"footballteam/user"
+)
+
+var (
+	ErrInvalidAPIKey = errors.New("invalid API key")
+	ErrInvalidScope  = errors.New("invalid API key scope")
+)
+
+// AllowedScopes adalah permission yang boleh diberikan ke API key. Endpoint GET
+// sudah publik, jadi key tanpa scope cukup untuk akses baca. Permission yang
+// butuh akun user (misalnya kepemilikan klub) sengaja tidak termasuk.
+var AllowedScopes = []user.Permission{
+	user.PermissionWriteMatches,
+	user.PermissionWriteMatchResults,
+}
+
+const (
+	keyPrefix         = "ftk"
+	keyIDSize         = 5
+	keySecretSize     = 32
+	lastUsedPrecision = time.Minute
+)
+
+type Service interface {
+	GetAPIKeys() ([]APIKey, error)
+	CreateAPIKey(input CreateAPIKeyInput) (APIKey, string, error)
+	RevokeAPIKey(id int) (APIKey, error)
+	Authenticate(plainKey string) (APIKey, error)
+}
+
+type service struct {
+	repository Repository
+}
+
+func NewService(repository Repository) *service {
+	return &service{repository}
+}
+
+func (s *service) GetAPIKeys() ([]APIKey, error) {
+	return s.repository.FindAll()
+}
+
+// CreateAPIKey mengembalikan key lengkap yang hanya bisa dilihat sekali.
+func (s *service) CreateAPIKey(input CreateAPIKeyInput) (APIKey, string, error) {
+	for _, scope := range input.Scopes {
+		if !isAllowedScope(scope) {
+			return APIKey{}, "", fmt.Errorf("%w: %s", ErrInvalidScope, scope)
+		}
+	}
+
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(time.Now()) {
+		return APIKey{}, "", errors.New("expires_at must be in the future")
+	}
+
+	idBytes := make([]byte, keyIDSize)
+	if _, err := rand.Read(idBytes); err != nil {
+		return APIKey{}, "", err
+	}
+	prefix := hex.EncodeToString(idBytes)
+
+	secret, err := helper.GenerateRandomToken(keySecretSize)
+	if err != nil {
+		return APIKey{}, "", err
+	}
+	plainKey := keyPrefix + "_" + prefix + "_" + secret
+
+	key := APIKey{
+		Name:        input.Name,
+		Prefix:      prefix,
+		KeyHash:     helper.HashToken(plainKey),
+		Scopes:      strings.Join(input.Scopes, ","),
+		ExpiresAt:   input.ExpiresAt,
+		CreatedByID: input.User.ID,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	newKey, err := s.repository.Create(key)
+	if err != nil {
+		return newKey, "", err
+	}
+
+	return newKey, plainKey, nil
+}
+
+func (s *service) RevokeAPIKey(id int) (APIKey, error) {
+	key, err := s.repository.FindByID(id)
+	if err != nil {
+		return key, err
+	}
+
+	if key.RevokedAt == nil {
+		now := time.Now()
+		key.RevokedAt = &now
+		key.UpdatedAt = now
+	}
+
+	return s.repository.Update(key)
+}
+
+func (s *service) Authenticate(plainKey string) (APIKey, error) {
+	parts := strings.SplitN(plainKey, "_", 3)
+	if len(parts) != 3 || parts[0] != keyPrefix {
+		return APIKey{}, ErrInvalidAPIKey
+	}
+
+	key, err := s.repository.FindByPrefix(parts[1])
+	if err != nil {
+		return APIKey{}, ErrInvalidAPIKey
+	}
+
+	hash := helper.HashToken(plainKey)
+	if subtle.ConstantTimeCompare([]byte(hash), []byte(key.KeyHash)) != 1 {
+		return APIKey{}, ErrInvalidAPIKey
+	}
+
+	now := time.Now()
+	if !key.IsActive(now) {
+		return APIKey{}, ErrInvalidAPIKey
+	}
+
+	// Cukup dicatat per menit supaya tidak menulis ke database di setiap request
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedPrecision {
+		if err := s.repository.TouchLastUsed(key.ID, now); err != nil {
+			return key, err
+		}
+		key.LastUsedAt = &now
+	}
+
+	return key, nil
+}
+
+func isAllowedScope(scope string) bool {
+	for _, allowed := range AllowedScopes {
+		if string(allowed) == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package audit
+
+import (
+	"reflect"
+	"time"
+
+	"gorm.io/gorm/schema"
+)
+
+type Change struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// Field yang selalu berubah atau tidak bermakna untuk audit
+var ignoredFields = map[string]bool{
+	"ID":        true,
+	"CreatedAt": true,
+	"UpdatedAt": true,
+	"DeletedAt": true,
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+var naming = schema.NamingStrategy{}
+
+// Diff membandingkan dua struct entity dan mengembalikan field yang berubah
+// dengan nama kolom database sebagai key. before nil berarti create, after
+// nil berarti delete. Relasi (struct atau slice entity lain) diabaikan.
+func Diff(before, after interface{}) map[string]Change {
+	beforeFields := fields(before)
+	afterFields := fields(after)
+
+	changes := map[string]Change{}
+	for name, to := range afterFields {
+		from, ok := beforeFields[name]
+		if ok && reflect.DeepEqual(from, to) {
+			continue
+		}
+		changes[name] = Change{From: from, To: to}
+	}
+	for name, from := range beforeFields {
+		if _, ok := afterFields[name]; !ok {
+			changes[name] = Change{From: from}
+		}
+	}
+
+	return changes
+}
+
+func fields(entity interface{}) map[string]interface{} {
+	if entity == nil {
+		return nil
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(entity))
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	result := map[string]interface{}{}
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if field.PkgPath != "" || ignoredFields[field.Name] || isRelation(field.Type) {
+			continue
+		}
+
+		fieldValue := value.Field(i)
+		if fieldValue.Kind() == reflect.Ptr {
+			if fieldValue.IsNil() {
+				result[naming.ColumnName("", field.Name)] = nil
+				continue
+			}
+			fieldValue = fieldValue.Elem()
+		}
+
+		result[naming.ColumnName("", field.Name)] = fieldValue.Interface()
+	}
+
+	return result
+}
+
+func isRelation(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		return t != timeType
+	case reflect.Slice:
+		return t.Elem().Kind() != reflect.Uint8
+	}
+
+	return false
+}
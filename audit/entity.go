@@ -0,0 +1,45 @@
+package audit
+
+import "time"
+
+const (
+	ActorUser   = "user"
+	ActorAPIKey = "api_key"
+
+	ActionCreate  = "create"
+	ActionUpdate  = "update"
+	ActionDelete  = "delete"
+	ActionRestore = "restore"
+	ActionPurge   = "purge"
+)
+
+// Actor adalah pihak yang melakukan perubahan: user yang login atau API key.
+type Actor struct {
+	Type string
+	ID   int
+	Name string
+}
+
+func UserActor(id int, name string) Actor {
+	return Actor{Type: ActorUser, ID: id, Name: name}
+}
+
+func APIKeyActor(id int, name string) Actor {
+	return Actor{Type: ActorAPIKey, ID: id, Name: name}
+}
+
+type Entry struct {
+	ID         int       `gorm:"primaryKey;autoIncrement"`
+	ActorType  string    `gorm:"size:20;not null;index:idx_audit_actor"`
+	ActorID    int       `gorm:"not null;index:idx_audit_actor"`
+	ActorName  string    `gorm:"size:100"`
+	Action     string    `gorm:"size:20;not null"`
+	EntityType string    `gorm:"size:50;not null;index:idx_audit_entity"`
+	EntityID   int       `gorm:"not null;index:idx_audit_entity"`
+	Changes    string    `gorm:"type:text"` // JSON: {"field": {"from": ..., "to": ...}}
+	CreatedAt  time.Time `gorm:"index"`
+}
+
+func (Entry) TableName() string {
+	return "audit_entries"
+}
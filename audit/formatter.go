@@ -0,0 +1,45 @@
+package audit
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type EntryFormatter struct {
+	ID         int             `json:"id"`
+	ActorType  string          `json:"actor_type"`
+	ActorID    int             `json:"actor_id"`
+	ActorName  string          `json:"actor_name"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityID   int             `json:"entity_id"`
+	Changes    json.RawMessage `json:"changes"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+func FormatEntry(entry Entry) EntryFormatter {
+	changes := json.RawMessage(entry.Changes)
+	if len(changes) == 0 {
+		changes = json.RawMessage("{}")
+	}
+
+	return EntryFormatter{
+		ID:         entry.ID,
+		ActorType:  entry.ActorType,
+		ActorID:    entry.ActorID,
+		ActorName:  entry.ActorName,
+		Action:     entry.Action,
+		EntityType: entry.EntityType,
+		EntityID:   entry.EntityID,
+		Changes:    changes,
+		CreatedAt:  entry.CreatedAt,
+	}
+}
+
+func FormatEntries(entries []Entry) []EntryFormatter {
+	formatted := []EntryFormatter{}
+	for _, e := range entries {
+		formatted = append(formatted, FormatEntry(e))
+	}
+	return formatted
+}
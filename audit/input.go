@@ -0,0 +1,14 @@
+package audit
+
+import "time"
+
+type ListEntriesInput struct {
+	ActorType  string    `form:"actor_type"`
+	ActorID    int       `form:"actor_id"`
+	Action     string    `form:"action"`
+	EntityType string    `form:"entity_type"`
+	EntityID   int       `form:"entity_id"`
+	From       time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To         time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Limit      int       `form:"limit" binding:"omitempty,min=1,max=200"`
+}
@@ -0,0 +1,51 @@
+package audit
+
+import "gorm.io/gorm"
+
+type Repository interface {
+	Create(entry Entry) (Entry, error)
+	FindAll(input ListEntriesInput) ([]Entry, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *repository {
+	return &repository{db}
+}
+
+func (r *repository) Create(entry Entry) (Entry, error) {
+	err := r.db.Create(&entry).Error
+	return entry, err
+}
+
+func (r *repository) FindAll(input ListEntriesInput) ([]Entry, error) {
+	var entries []Entry
+
+	query := r.db.Order("created_at desc").Order("id desc").Limit(input.Limit)
+	if input.ActorType != "" {
+		query = query.Where("actor_type = ?", input.ActorType)
+	}
+	if input.ActorID != 0 {
+		query = query.Where("actor_id = ?", input.ActorID)
+	}
+	if input.Action != "" {
+		query = query.Where("action = ?", input.Action)
+	}
+	if input.EntityType != "" {
+		query = query.Where("entity_type = ?", input.EntityType)
+	}
+	if input.EntityID != 0 {
+		query = query.Where("entity_id = ?", input.EntityID)
+	}
+	if !input.From.IsZero() {
+		query = query.Where("created_at >= ?", input.From)
+	}
+	if !input.To.IsZero() {
+		query = query.Where("created_at <= ?", input.To)
+	}
+
+	err := query.Find(&entries).Error
+	return entries, err
+}
@@ -0,0 +1,60 @@
+package audit
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+)
+
+const DefaultListLimit = 50
+
+type Service interface {
+	Record(actor Actor, action string, entityType string, entityID int, before, after interface{})
+	GetEntries(input ListEntriesInput) ([]Entry, error)
+}
+
+type service struct {
+	repository Repository
+}
+
+func NewService(repository Repository) *service {
+	return &service{repository}
+}
+
+// Record dipanggil setelah perubahan tersimpan. Gagal menulis audit tidak
+// membatalkan perubahan yang sudah terjadi, jadi error hanya dicatat di log.
+func (s *service) Record(actor Actor, action string, entityType string, entityID int, before, after interface{}) {
+	changes := Diff(before, after)
+	if action == ActionUpdate && len(changes) == 0 {
+		return
+	}
+
+	encoded, err := json.Marshal(changes)
+	if err != nil {
+		log.Printf("❌ Failed to encode audit entry for %s %d: %v", entityType, entityID, err)
+		return
+	}
+
+	entry := Entry{
+		ActorType:  actor.Type,
+		ActorID:    actor.ID,
+		ActorName:  actor.Name,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Changes:    string(encoded),
+		CreatedAt:  time.Now(),
+	}
+
+	if _, err := s.repository.Create(entry); err != nil {
+		log.Printf("❌ Failed to write audit entry for %s %d: %v", entityType, entityID, err)
+	}
+}
+
+func (s *service) GetEntries(input ListEntriesInput) ([]Entry, error) {
+	if input.Limit == 0 {
+		input.Limit = DefaultListLimit
+	}
+
+	return s.repository.FindAll(input)
+}
@@ -0,0 +1,36 @@
+package auth
+
+import "time"
+
+// RefreshToken disimpan dalam bentuk hash. Setiap token yang dirotasi tetap
+// berada dalam FamilyID yang sama sehingga pemakaian ulang token lama bisa
+// dideteksi dan seluruh keluarganya dicabut.
+type RefreshToken struct {
+	ID           int        `gorm:"primaryKey;autoIncrement"`
+	UserID       int        `gorm:"not null;index"`
+	TokenHash    string     `gorm:"size:64;uniqueIndex;not null"`
+	FamilyID     string     `gorm:"size:64;not null;index"`
+	ExpiresAt    time.Time  `gorm:"not null"`
+	RevokedAt    *time.Time `gorm:"index"`
+	ReplacedByID int
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// RevokedToken adalah access token (berdasarkan jti) yang dicabut sebelum
+// kedaluwarsa, misalnya karena logout. Baris boleh dihapus setelah ExpiresAt.
+type RevokedToken struct {
+	ID        int       `gorm:"primaryKey;autoIncrement"`
+	JTI       string    `gorm:"size:64;uniqueIndex;not null"`
+	UserID    int       `gorm:"not null;index"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+	CreatedAt time.Time
+}
+
+// UserRevocation membatalkan semua access token milik user yang diterbitkan
+// sebelum RevokedBefore ("logout dari semua perangkat" atau ganti password).
+type UserRevocation struct {
+	UserID        int       `gorm:"primaryKey;autoIncrement:false"`
+	RevokedBefore time.Time `gorm:"not null"`
+	UpdatedAt     time.Time
+}
@@ -0,0 +1,217 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt"
+)
+
+var (
+	ErrUnknownKey     = errors.New("unknown signing key")
+	ErrUnsupportedKey = errors.New("unsupported key type")
+)
+
+const (
+	minRSAKeySizeBits  = 2048
+	minHMACSecretBytes = 32
+)
+
+// Key adalah satu kunci di keyring. SignKey kosong berarti kunci hanya dipakai
+// untuk verifikasi (misalnya kunci lama yang sedang dirotasi keluar).
+type Key struct {
+	ID        string
+	Method    jwt.SigningMethod
+	SignKey   interface{}
+	VerifyKey interface{}
+}
+
+type Keyring struct {
+	activeID string
+	keys     map[string]Key
+}
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+func NewKeyring(activeID string, keys ...Key) (*Keyring, error) {
+	keyring := &Keyring{activeID: activeID, keys: map[string]Key{}}
+
+	for _, key := range keys {
+		if _, exists := keyring.keys[key.ID]; exists {
+			return nil, fmt.Errorf("duplicate key id %q", key.ID)
+		}
+		keyring.keys[key.ID] = key
+	}
+
+	active, ok := keyring.keys[activeID]
+	if !ok {
+		return nil, fmt.Errorf("active key %q not found in keyring", activeID)
+	}
+	if active.SignKey == nil {
+		return nil, fmt.Errorf("active key %q has no private key", activeID)
+	}
+
+	return keyring, nil
+}
+
+func (k *Keyring) Active() Key {
+	return k.keys[k.activeID]
+}
+
+func (k *Keyring) Find(id string) (Key, bool) {
+	key, ok := k.keys[id]
+	return key, ok
+}
+
+// JWKS hanya memuat kunci publik asimetris; secret HMAC tidak pernah dipublikasikan.
+func (k *Keyring) JWKS() JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+
+	ids := make([]string, 0, len(k.keys))
+	for id := range k.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		key := k.keys[id]
+		switch publicKey := key.VerifyKey.(type) {
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				Kty: "RSA",
+				Kid: key.ID,
+				Use: "sig",
+				Alg: key.Method.Alg(),
+				N:   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				Kty: "OKP",
+				Kid: key.ID,
+				Use: "sig",
+				Alg: key.Method.Alg(),
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(publicKey),
+			})
+		}
+	}
+
+	return set
+}
+
+func NewHMACKey(id string, secret []byte) (Key, error) {
+	if len(secret) < minHMACSecretBytes {
+		return Key{}, fmt.Errorf("HMAC secret for key %q must be at least %d bytes", id, minHMACSecretBytes)
+	}
+
+	return Key{ID: id, Method: jwt.SigningMethodHS256, SignKey: secret, VerifyKey: secret}, nil
+}
+
+// ParsePEMKey menerima private key (PKCS#1/PKCS#8) maupun public key (PKIX)
+// RSA atau Ed25519.
+func ParsePEMKey(id string, data []byte) (Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return Key{}, fmt.Errorf("key %q is not valid PEM", id)
+	}
+
+	var parsed interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return Key{}, fmt.Errorf("key %q: unsupported PEM block %q", id, block.Type)
+	}
+	if err != nil {
+		return Key{}, fmt.Errorf("key %q: %w", id, err)
+	}
+
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		if k.N.BitLen() < minRSAKeySizeBits {
+			return Key{}, fmt.Errorf("RSA key %q must be at least %d bits", id, minRSAKeySizeBits)
+		}
+		return Key{ID: id, Method: jwt.SigningMethodRS256, SignKey: k, VerifyKey: &k.PublicKey}, nil
+	case *rsa.PublicKey:
+		if k.N.BitLen() < minRSAKeySizeBits {
+			return Key{}, fmt.Errorf("RSA key %q must be at least %d bits", id, minRSAKeySizeBits)
+		}
+		return Key{ID: id, Method: jwt.SigningMethodRS256, VerifyKey: k}, nil
+	case ed25519.PrivateKey:
+		return Key{ID: id, Method: jwt.SigningMethodEdDSA, SignKey: k, VerifyKey: k.Public().(ed25519.PublicKey)}, nil
+	case ed25519.PublicKey:
+		return Key{ID: id, Method: jwt.SigningMethodEdDSA, VerifyKey: k}, nil
+	}
+
+	return Key{}, fmt.Errorf("key %q: %w", id, ErrUnsupportedKey)
+}
+
+// LoadKeysFromDir membaca semua kunci di dir. Nama file tanpa ekstensi menjadi
+// kid: "*.pem" untuk RSA/Ed25519 dan "*.secret" untuk secret HS256.
+func LoadKeysFromDir(dir string) ([]Key, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []Key
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		ext := filepath.Ext(entry.Name())
+		id := strings.TrimSuffix(entry.Name(), ext)
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		var key Key
+		switch ext {
+		case ".pem":
+			key, err = ParsePEMKey(id, data)
+		case ".secret":
+			key, err = NewHMACKey(id, []byte(strings.TrimSpace(string(data))))
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
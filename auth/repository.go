@@ -0,0 +1,99 @@
+package auth
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	CreateRefreshToken(token RefreshToken) (RefreshToken, error)
+	FindRefreshTokenByHash(hash string) (RefreshToken, error)
+	UpdateRefreshToken(token RefreshToken) (RefreshToken, error)
+	RevokeRefreshToken(id int, revokedAt time.Time) (bool, error)
+	RevokeRefreshTokenFamily(familyID string, revokedAt time.Time) error
+	RevokeRefreshTokensByUserID(userID int, revokedAt time.Time) error
+	CreateRevokedToken(token RevokedToken) (RevokedToken, error)
+	FindRevokedTokens(after time.Time) ([]RevokedToken, error)
+	DeleteRevokedTokens(before time.Time) error
+	SaveUserRevocation(revocation UserRevocation) (UserRevocation, error)
+	FindUserRevocations() ([]UserRevocation, error)
+	WithTx(tx *gorm.DB) Repository
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *repository {
+	return &repository{db}
+}
+
+// WithTx mengembalikan repository yang menulis lewat transaksi tx.
+func (r *repository) WithTx(tx *gorm.DB) Repository {
+	return &repository{tx}
+}
+
+func (r *repository) CreateRefreshToken(token RefreshToken) (RefreshToken, error) {
+	err := r.db.Create(&token).Error
+	return token, err
+}
+
+func (r *repository) FindRefreshTokenByHash(hash string) (RefreshToken, error) {
+	var token RefreshToken
+	err := r.db.Where("token_hash = ?", hash).First(&token).Error
+	return token, err
+}
+
+func (r *repository) UpdateRefreshToken(token RefreshToken) (RefreshToken, error) {
+	err := r.db.Save(&token).Error
+	return token, err
+}
+
+// RevokeRefreshToken hanya berhasil jika token belum dicabut, sehingga dua
+// request refresh yang bersamaan tidak bisa sama-sama memakai token yang sama.
+func (r *repository) RevokeRefreshToken(id int, revokedAt time.Time) (bool, error) {
+	result := r.db.Model(&RefreshToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", revokedAt)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *repository) RevokeRefreshTokenFamily(familyID string, revokedAt time.Time) error {
+	return r.db.Model(&RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", revokedAt).Error
+}
+
+func (r *repository) RevokeRefreshTokensByUserID(userID int, revokedAt time.Time) error {
+	return r.db.Model(&RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", revokedAt).Error
+}
+
+func (r *repository) CreateRevokedToken(token RevokedToken) (RevokedToken, error) {
+	err := r.db.Where(RevokedToken{JTI: token.JTI}).FirstOrCreate(&token).Error
+	return token, err
+}
+
+// FindRevokedTokens hanya mengambil token yang belum kedaluwarsa
+func (r *repository) FindRevokedTokens(after time.Time) ([]RevokedToken, error) {
+	var tokens []RevokedToken
+	err := r.db.Where("expires_at > ?", after).Find(&tokens).Error
+	return tokens, err
+}
+
+func (r *repository) DeleteRevokedTokens(before time.Time) error {
+	return r.db.Where("expires_at <= ?", before).Delete(&RevokedToken{}).Error
+}
+
+func (r *repository) SaveUserRevocation(revocation UserRevocation) (UserRevocation, error) {
+	err := r.db.Save(&revocation).Error
+	return revocation, err
+}
+
+func (r *repository) FindUserRevocations() ([]UserRevocation, error) {
+	var revocations []UserRevocation
+	err := r.db.Find(&revocations).Error
+	return revocations, err
+}
@@ -0,0 +1,104 @@
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultRevocationRefresh adalah jeda maksimum sebelum cache membaca ulang
+// daftar pencabutan dari database, supaya logout di instance lain ikut terbaca.
+const DefaultRevocationRefresh = 30 * time.Second
+
+// revocationCache menyimpan salinan in-memory dari tabel revoked_tokens dan
+// user_revocations sehingga validasi token tidak perlu query di setiap request.
+type revocationCache struct {
+	repository Repository
+	interval   time.Duration
+
+	mu       sync.RWMutex
+	loadedAt time.Time
+	tokens   map[string]time.Time
+	users    map[int]time.Time
+}
+
+func newRevocationCache(repository Repository, interval time.Duration) *revocationCache {
+	return &revocationCache{
+		repository: repository,
+		interval:   interval,
+		tokens:     map[string]time.Time{},
+		users:      map[int]time.Time{},
+	}
+}
+
+// isRevoked membandingkan issuedAt dengan waktu pencabutan user pada presisi
+// milidetik. Token lama dengan iat bilangan bulat dianggap terbit di awal
+// detiknya, jadi token yang terbit di detik pencabutan tetap ditolak.
+func (c *revocationCache) isRevoked(jti string, userID int, issuedAt time.Time) (bool, error) {
+	if err := c.refreshIfStale(); err != nil {
+		return false, err
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if _, ok := c.tokens[jti]; ok {
+		return true, nil
+	}
+	if revokedBefore, ok := c.users[userID]; ok && issuedAt.UnixMilli() < revokedBefore.UnixMilli() {
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func (c *revocationCache) addToken(jti string, expiresAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tokens[jti] = expiresAt
+}
+
+func (c *revocationCache) addUser(userID int, revokedBefore time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.users[userID] = revokedBefore
+}
+
+func (c *revocationCache) refreshIfStale() error {
+	c.mu.RLock()
+	fresh := time.Since(c.loadedAt) < c.interval
+	c.mu.RUnlock()
+	if fresh {
+		return nil
+	}
+
+	now := time.Now()
+	if err := c.repository.DeleteRevokedTokens(now); err != nil {
+		return err
+	}
+
+	revokedTokens, err := c.repository.FindRevokedTokens(now)
+	if err != nil {
+		return err
+	}
+	userRevocations, err := c.repository.FindUserRevocations()
+	if err != nil {
+		return err
+	}
+
+	tokens := make(map[string]time.Time, len(revokedTokens))
+	for _, t := range revokedTokens {
+		tokens[t.JTI] = t.ExpiresAt
+	}
+	users := make(map[int]time.Time, len(userRevocations))
+	for _, u := range userRevocations {
+		users[u.UserID] = u.RevokedBefore
+	}
+
+	c.mu.Lock()
+	c.tokens = tokens
+	c.users = users
+	c.loadedAt = now
+	c.mu.Unlock()
+
+	return nil
+}
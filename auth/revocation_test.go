@@ -0,0 +1,118 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// fakeRepository menyimpan pencabutan di memori.
+type fakeRepository struct {
+	Repository
+	revocations map[int]UserRevocation
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{revocations: map[int]UserRevocation{}}
+}
+
+func (r *fakeRepository) WithTx(tx *gorm.DB) Repository { return r }
+
+func (r *fakeRepository) RevokeRefreshTokensByUserID(userID int, revokedAt time.Time) error {
+	return nil
+}
+
+func (r *fakeRepository) SaveUserRevocation(revocation UserRevocation) (UserRevocation, error) {
+	r.revocations[revocation.UserID] = revocation
+	return revocation, nil
+}
+
+func (r *fakeRepository) FindUserRevocations() ([]UserRevocation, error) {
+	var revocations []UserRevocation
+	for _, revocation := range r.revocations {
+		revocations = append(revocations, revocation)
+	}
+	return revocations, nil
+}
+
+func (r *fakeRepository) FindRevokedTokens(after time.Time) ([]RevokedToken, error) {
+	return nil, nil
+}
+
+func (r *fakeRepository) DeleteRevokedTokens(before time.Time) error {
+	return nil
+}
+
+func TestIsRevokedAtRevocationSecond(t *testing.T) {
+	revokedAt := time.Date(2025, 3, 1, 12, 0, 0, 500*int(time.Millisecond), time.UTC)
+
+	cache := newRevocationCache(newFakeRepository(), time.Hour)
+	cache.loadedAt = time.Now()
+	cache.addUser(1, revokedAt)
+
+	tests := []struct {
+		name     string
+		userID   int
+		issuedAt time.Time
+		want     bool
+	}{
+		{"previous second", 1, revokedAt.Add(-time.Second), true},
+		{"legacy whole-second iat in the same second", 1, revokedAt.Truncate(time.Second), true},
+		{"same second, just before", 1, revokedAt.Add(-time.Millisecond), true},
+		{"same millisecond", 1, revokedAt, false},
+		{"same second, just after", 1, revokedAt.Add(time.Millisecond), false},
+		{"next second", 1, revokedAt.Add(time.Second), false},
+		{"other user", 2, revokedAt.Add(-time.Hour), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			revoked, err := cache.isRevoked("jti", tt.userID, tt.issuedAt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if revoked != tt.want {
+				t.Errorf("isRevoked(%s) = %v, want %v", tt.issuedAt.Format(time.RFC3339Nano), revoked, tt.want)
+			}
+		})
+	}
+}
+
+// Login ulang tepat setelah ganti password harus menghasilkan token yang
+// berlaku, meskipun terbit di detik yang sama dengan pencabutan.
+func TestTokenIssuedAfterRevocationIsValid(t *testing.T) {
+	key, err := NewHMACKey("test", []byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyring, err := NewKeyring("test", key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewService(newFakeRepository(), Config{Keyring: keyring})
+
+	oldToken, err := s.GenerateToken(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(2 * time.Millisecond)
+
+	if err := s.RevokeUserTokens(1); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(2 * time.Millisecond)
+
+	newToken, err := s.GenerateToken(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.ValidateToken(oldToken); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("old token: err = %v, want ErrTokenRevoked", err)
+	}
+	if _, err := s.ValidateToken(newToken); err != nil {
+		t.Errorf("new token: err = %v, want nil", err)
+	}
+}
@@ -2,32 +2,147 @@ package auth
 
 import (
 	"errors"
+	"math"
+	"time"
+
+	"footballteam/helper"
 
 	"github.com/golang-jwt/jwt"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTokenExpired        = errors.New("token has expired")
+	ErrTokenRevoked        = errors.New("token has been revoked")
+	ErrInvalidToken        = errors.New("invalid token")
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenExpired = errors.New("refresh token has expired")
+	ErrRefreshTokenReused  = errors.New("refresh token has already been used")
+)
+
+const (
+	DefaultAccessTokenTTL  = 15 * time.Minute
+	DefaultRefreshTokenTTL = 30 * 24 * time.Hour
+	ChallengeTokenTTL      = 5 * time.Minute
+
+	// Purpose untuk challenge token login dua langkah
+	PurposeTwoFactor       = "2fa"
+	PurposeTwoFactorEnroll = "2fa_enroll"
+
+	refreshTokenSize = 32
+	tokenIDSize      = 16
 )
 
 type Service interface {
 	GenerateToken(userID int) (string, error)
 	ValidateToken(token string) (*jwt.Token, error)
+	GenerateRefreshToken(userID int) (string, error)
+	RotateRefreshToken(token string) (int, string, error)
+	AccessTokenTTL() time.Duration
+	JWKS() JWKSet
+	RevokeToken(token *jwt.Token) error
+	RevokeRefreshToken(userID int, token string) error
+	RevokeUserTokens(userID int) error
+	RevokeUserTokensTx(tx *gorm.DB, userID int) error
+	GenerateChallengeToken(userID int, purpose string) (string, error)
+	ValidateChallengeToken(token string, purpose string) (int, error)
+}
+
+type Config struct {
+	Keyring           *Keyring
+	AccessTokenTTL    time.Duration
+	RefreshTokenTTL   time.Duration
+	RevocationRefresh time.Duration
 }
 
 type jwtService struct {
+	repository  Repository
+	config      Config
+	revocations *revocationCache
+}
 
+func NewService(repository Repository, config Config) *jwtService {
+	if config.AccessTokenTTL <= 0 {
+		config.AccessTokenTTL = DefaultAccessTokenTTL
+	}
+	if config.RefreshTokenTTL <= 0 {
+		config.RefreshTokenTTL = DefaultRefreshTokenTTL
+	}
+	if config.RevocationRefresh <= 0 {
+		config.RevocationRefresh = DefaultRevocationRefresh
+	}
+
+	return &jwtService{repository, config, newRevocationCache(repository, config.RevocationRefresh)}
 }
 
-var SECRET_KEY = []byte("FOOTBALLTEAM_secret_key")
+func (s *jwtService) AccessTokenTTL() time.Duration {
+	return s.config.AccessTokenTTL
+}
 
-func NewService() *jwtService {
-	return &jwtService{}
+func (s *jwtService) JWKS() JWKSet {
+	return s.config.Keyring.JWKS()
 }
 
 func (s *jwtService) GenerateToken(userID int) (string, error) {
+	tokenID, err := helper.GenerateRandomToken(tokenIDSize)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
 	claim := jwt.MapClaims{}
 	claim["user_id"] = userID
+	claim["jti"] = tokenID
+	// iat dengan presisi milidetik supaya token yang terbit tepat setelah
+	// pencabutan (misalnya login ulang setelah ganti password) tidak ikut ditolak
+	claim["iat"] = float64(now.UnixMilli()) / 1000
+	claim["exp"] = now.Add(s.config.AccessTokenTTL).Unix()
+
+	return s.sign(claim)
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claim)
+// GenerateChallengeToken menerbitkan token berumur pendek untuk langkah
+// kedua login. Token ini ditolak oleh ValidateToken sehingga tidak bisa
+// dipakai sebagai access token.
+func (s *jwtService) GenerateChallengeToken(userID int, purpose string) (string, error) {
+	now := time.Now()
+	claim := jwt.MapClaims{}
+	claim["user_id"] = userID
+	claim["purpose"] = purpose
+	claim["iat"] = now.Unix()
+	claim["exp"] = now.Add(ChallengeTokenTTL).Unix()
 
-	signedToken, err := token.SignedString(SECRET_KEY)
+	return s.sign(claim)
+}
+
+func (s *jwtService) ValidateChallengeToken(encodedToken string, purpose string) (int, error) {
+	token, err := jwt.Parse(encodedToken, s.verificationKey)
+	if err != nil {
+		return 0, ErrInvalidToken
+	}
+
+	claim, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid || !claim.VerifyExpiresAt(time.Now().Unix(), true) {
+		return 0, ErrInvalidToken
+	}
+	if tokenPurpose, _ := claim["purpose"].(string); tokenPurpose != purpose {
+		return 0, ErrInvalidToken
+	}
+
+	userID, ok := claim["user_id"].(float64)
+	if !ok {
+		return 0, ErrInvalidToken
+	}
+
+	return int(userID), nil
+}
+
+func (s *jwtService) sign(claim jwt.MapClaims) (string, error) {
+	key := s.config.Keyring.Active()
+	token := jwt.NewWithClaims(key.Method, claim)
+	token.Header["kid"] = key.ID
+
+	signedToken, err := token.SignedString(key.SignKey)
 	if err != nil {
 		return signedToken, err
 	}
@@ -36,19 +151,212 @@ func (s *jwtService) GenerateToken(userID int) (string, error) {
 }
 
 func (s *jwtService) ValidateToken(encodedToken string) (*jwt.Token, error) {
-	token, err := jwt.Parse(encodedToken, func(token *jwt.Token) (interface{}, error) {
-		_, ok := token.Method.(*jwt.SigningMethodHMAC)
+	token, err := jwt.Parse(encodedToken, s.verificationKey)
 
-		if !ok {
-			return nil, errors.New("invalid token")
+	if err != nil {
+		var validationErr *jwt.ValidationError
+		if errors.As(err, &validationErr) && validationErr.Errors&jwt.ValidationErrorExpired != 0 {
+			return token, ErrTokenExpired
 		}
+		return token, err
+	}
 
-		return []byte(SECRET_KEY), nil
-	})
+	// Token lama tanpa exp tidak boleh berlaku selamanya
+	claim, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !claim.VerifyExpiresAt(time.Now().Unix(), true) {
+		return token, ErrTokenExpired
+	}
+
+	// Challenge token 2FA bukan access token
+	if _, isChallenge := claim["purpose"]; isChallenge {
+		return token, ErrInvalidToken
+	}
 
+	tokenID, _ := claim["jti"].(string)
+	userID, _ := claim["user_id"].(float64)
+	issuedAt, _ := claim["iat"].(float64)
+	if tokenID == "" {
+		return token, ErrInvalidToken
+	}
+
+	revoked, err := s.revocations.isRevoked(tokenID, int(userID), time.UnixMilli(int64(math.Round(issuedAt*1000))))
 	if err != nil {
 		return token, err
 	}
+	if revoked {
+		return token, ErrTokenRevoked
+	}
 
 	return token, nil
-}
\ No newline at end of file
+}
+
+// RevokeToken mencabut satu access token (logout dari sesi saat ini).
+func (s *jwtService) RevokeToken(token *jwt.Token) error {
+	claim, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return ErrInvalidToken
+	}
+
+	tokenID, _ := claim["jti"].(string)
+	userID, _ := claim["user_id"].(float64)
+	expiresAt, _ := claim["exp"].(float64)
+	if tokenID == "" {
+		return ErrInvalidToken
+	}
+
+	revoked, err := s.repository.CreateRevokedToken(RevokedToken{
+		JTI:       tokenID,
+		UserID:    int(userID),
+		ExpiresAt: time.Unix(int64(expiresAt), 0),
+	})
+	if err != nil {
+		return err
+	}
+
+	s.revocations.addToken(revoked.JTI, revoked.ExpiresAt)
+	return nil
+}
+
+// RevokeRefreshToken mencabut refresh token milik userID beserta seluruh
+// token hasil rotasinya.
+func (s *jwtService) RevokeRefreshToken(userID int, encodedToken string) error {
+	token, err := s.repository.FindRefreshTokenByHash(helper.HashToken(encodedToken))
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && token.UserID != userID) {
+		return ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.repository.RevokeRefreshTokenFamily(token.FamilyID, time.Now())
+}
+
+// RevokeUserTokens membatalkan semua access token dan refresh token milik
+// user, dipakai untuk "logout dari semua perangkat" dan saat password berubah.
+func (s *jwtService) RevokeUserTokens(userID int) error {
+	return s.revokeUserTokens(s.repository, userID)
+}
+
+// RevokeUserTokensTx sama dengan RevokeUserTokens tetapi menulis lewat
+// transaksi tx, supaya pencabutan tersimpan bersama perubahan password. Cache
+// langsung diperbarui; jika transaksi di-rollback, user hanya perlu login
+// ulang sampai cache dibaca ulang dari database.
+func (s *jwtService) RevokeUserTokensTx(tx *gorm.DB, userID int) error {
+	return s.revokeUserTokens(s.repository.WithTx(tx), userID)
+}
+
+func (s *jwtService) revokeUserTokens(repository Repository, userID int) error {
+	now := time.Now()
+
+	if err := repository.RevokeRefreshTokensByUserID(userID, now); err != nil {
+		return err
+	}
+
+	revocation, err := repository.SaveUserRevocation(UserRevocation{UserID: userID, RevokedBefore: now})
+	if err != nil {
+		return err
+	}
+
+	s.revocations.addUser(revocation.UserID, revocation.RevokedBefore)
+	return nil
+}
+
+// verificationKey memilih kunci berdasarkan header kid. Token tanpa kid
+// diverifikasi dengan kunci aktif. Algoritma token harus sama dengan
+// algoritma kunci agar tidak bisa ditukar (misalnya RS256 menjadi HS256).
+func (s *jwtService) verificationKey(token *jwt.Token) (interface{}, error) {
+	key := s.config.Keyring.Active()
+	if kid, ok := token.Header["kid"].(string); ok {
+		found, exists := s.config.Keyring.Find(kid)
+		if !exists {
+			return nil, ErrUnknownKey
+		}
+		key = found
+	}
+
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, ErrInvalidToken
+	}
+
+	return key.VerifyKey, nil
+}
+
+func (s *jwtService) GenerateRefreshToken(userID int) (string, error) {
+	familyID, err := helper.GenerateRandomToken(tokenIDSize)
+	if err != nil {
+		return "", err
+	}
+
+	_, encodedToken, err := s.issueRefreshToken(userID, familyID)
+	return encodedToken, err
+}
+
+// RotateRefreshToken menukar refresh token dengan token baru dan mengembalikan
+// user ID pemiliknya. Token yang sudah pernah dipakai akan mencabut seluruh
+// keluarga token tersebut.
+func (s *jwtService) RotateRefreshToken(encodedToken string) (int, string, error) {
+	current, err := s.repository.FindRefreshTokenByHash(helper.HashToken(encodedToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, "", ErrInvalidRefreshToken
+		}
+		return 0, "", err
+	}
+
+	now := time.Now()
+
+	if current.RevokedAt != nil {
+		if err := s.repository.RevokeRefreshTokenFamily(current.FamilyID, now); err != nil {
+			return 0, "", err
+		}
+		return 0, "", ErrRefreshTokenReused
+	}
+
+	if now.After(current.ExpiresAt) {
+		return 0, "", ErrRefreshTokenExpired
+	}
+
+	revoked, err := s.repository.RevokeRefreshToken(current.ID, now)
+	if err != nil {
+		return 0, "", err
+	}
+	if !revoked {
+		// Token yang sama dipakai oleh request lain secara bersamaan
+		if err := s.repository.RevokeRefreshTokenFamily(current.FamilyID, now); err != nil {
+			return 0, "", err
+		}
+		return 0, "", ErrRefreshTokenReused
+	}
+
+	next, newToken, err := s.issueRefreshToken(current.UserID, current.FamilyID)
+	if err != nil {
+		return 0, "", err
+	}
+
+	current.RevokedAt = &now
+	current.ReplacedByID = next.ID
+	if _, err := s.repository.UpdateRefreshToken(current); err != nil {
+		return 0, "", err
+	}
+
+	return current.UserID, newToken, nil
+}
+
+func (s *jwtService) issueRefreshToken(userID int, familyID string) (RefreshToken, string, error) {
+	encodedToken, err := helper.GenerateRandomToken(refreshTokenSize)
+	if err != nil {
+		return RefreshToken{}, "", err
+	}
+
+	token, err := s.repository.CreateRefreshToken(RefreshToken{
+		UserID:    userID,
+		TokenHash: helper.HashToken(encodedToken),
+		FamilyID:  familyID,
+		ExpiresAt: time.Now().Add(s.config.RefreshTokenTTL),
+	})
+	if err != nil {
+		return token, "", err
+	}
+
+	return token, encodedToken, nil
+}
@@ -0,0 +1,21 @@
+package handler
+
+import (
+	"footballteam/apikey"
+	"footballteam/audit"
+	"footballteam/user"
+
+	"github.com/gin-gonic/gin"
+)
+
+// currentActor mengembalikan pelaku request untuk audit log, baik user yang
+// login maupun API key.
+func currentActor(c *gin.Context) audit.Actor {
+	if value, ok := c.Get("currentAPIKey"); ok {
+		key := value.(apikey.APIKey)
+		return audit.APIKeyActor(key.ID, key.Name)
+	}
+
+	currentUser := c.MustGet("currentUser").(user.User)
+	return audit.UserActor(currentUser.ID, currentUser.Name)
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"footballteam/apikey"
+	"footballteam/helper"
+	"footballteam/user"
+)
+
+type apiKeyHandler struct {
+	apiKeyService apikey.Service
+}
+
+func NewAPIKeyHandler(apiKeyService apikey.Service) *apiKeyHandler {
+	return &apiKeyHandler{apiKeyService}
+}
+
+// GET /api_keys
+func (h *apiKeyHandler) GetAPIKeys(c *gin.Context) {
+	keys, err := h.apiKeyService.GetAPIKeys()
+	if err != nil {
+		response := helper.APIResponse("Failed to get API keys", http.StatusInternalServerError, "error", err.Error())
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helper.APIResponse("List of API keys", http.StatusOK, "success", apikey.FormatAPIKeys(keys))
+	c.JSON(http.StatusOK, response)
+}
+
+// POST /api_keys
+func (h *apiKeyHandler) CreateAPIKey(c *gin.Context) {
+	var input apikey.CreateAPIKeyInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response := helper.APIResponse("Create API key failed", http.StatusUnprocessableEntity, "error", err.Error())
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	input.User = c.MustGet("currentUser").(user.User)
+
+	newKey, plainKey, err := h.apiKeyService.CreateAPIKey(input)
+	if err != nil {
+		response := helper.APIResponse("Create API key failed", http.StatusBadRequest, "error", err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	formatter := apikey.FormatAPIKey(newKey)
+	formatter.Key = plainKey
+
+	response := helper.APIResponse("API key created, store the key now because it will not be shown again", http.StatusCreated, "success", formatter)
+	c.JSON(http.StatusCreated, response)
+}
+
+// DELETE /api_keys/:id
+func (h *apiKeyHandler) RevokeAPIKey(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helper.APIResponse("Invalid API key ID", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	key, err := h.apiKeyService.RevokeAPIKey(id)
+	if err != nil {
+		response := helper.APIResponse("API key not found", http.StatusNotFound, "error", nil)
+		c.JSON(http.StatusNotFound, response)
+		return
+	}
+
+	response := helper.APIResponse("API key revoked", http.StatusOK, "success", apikey.FormatAPIKey(key))
+	c.JSON(http.StatusOK, response)
+}
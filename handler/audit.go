@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"footballteam/audit"
+	"footballteam/helper"
+)
+
+type auditHandler struct {
+	auditService audit.Service
+}
+
+func NewAuditHandler(auditService audit.Service) *auditHandler {
+	return &auditHandler{auditService}
+}
+
+// GET /audit?entity_type=team&entity_id=1&actor_id=2&action=update&from=...&to=...
+func (h *auditHandler) GetEntries(c *gin.Context) {
+	var input audit.ListEntriesInput
+	if err := c.ShouldBindQuery(&input); err != nil {
+		response := helper.APIResponse("Invalid audit filter", http.StatusUnprocessableEntity, "error", helper.FormatValidationError(err))
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	entries, err := h.auditService.GetEntries(input)
+	if err != nil {
+		response := helper.APIResponse("Failed to get audit log", http.StatusInternalServerError, "error", err.Error())
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helper.APIResponse("Audit log", http.StatusOK, "success", audit.FormatEntries(entries))
+	c.JSON(http.StatusOK, response)
+}
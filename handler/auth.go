@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"footballteam/auth"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type authHandler struct {
+	authService auth.Service
+}
+
+func NewAuthHandler(authService auth.Service) *authHandler {
+	return &authHandler{authService}
+}
+
+// GET /.well-known/jwks.json
+// Formatnya mengikuti RFC 7517 (tanpa helper.APIResponse) agar bisa dibaca
+// langsung oleh library JWT di service lain.
+func (h *authHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.authService.JWKS())
+}
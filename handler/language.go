@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"footballteam/player"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/text/language"
+)
+
+// Urutan penting: bahasa pertama menjadi default jika header kosong atau
+// tidak ada bahasa yang cocok.
+var (
+	supportedLanguages = []string{player.LangID, player.LangEN}
+	languageMatcher    = language.NewMatcher([]language.Tag{language.Indonesian, language.English})
+)
+
+// requestLanguage memilih bahasa label dari header Accept-Language, misalnya
+// "en-US,en;q=0.9" menghasilkan "en".
+func requestLanguage(c *gin.Context) string {
+	tags, _, err := language.ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+	if err != nil || len(tags) == 0 {
+		return supportedLanguages[0]
+	}
+
+	_, index, confidence := languageMatcher.Match(tags...)
+	if confidence == language.No {
+		return supportedLanguages[0]
+	}
+	return supportedLanguages[index]
+}
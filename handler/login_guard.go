@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"footballteam/helper"
+	"footballteam/loginguard"
+	"footballteam/user"
+)
+
+type loginGuardHandler struct {
+	loginGuardService loginguard.Service
+}
+
+func NewLoginGuardHandler(loginGuardService loginguard.Service) *loginGuardHandler {
+	return &loginGuardHandler{loginGuardService}
+}
+
+// GET /lockouts?active=true
+func (h *loginGuardHandler) GetLockouts(c *gin.Context) {
+	activeOnly := c.Query("active") == "true"
+
+	lockouts, err := h.loginGuardService.GetLockouts(activeOnly)
+	if err != nil {
+		response := helper.APIResponse("Failed to get lockouts", http.StatusInternalServerError, "error", err.Error())
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helper.APIResponse("List of lockouts", http.StatusOK, "success", loginguard.FormatLockouts(lockouts))
+	c.JSON(http.StatusOK, response)
+}
+
+// DELETE /lockouts/:id
+func (h *loginGuardHandler) ClearLockout(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helper.APIResponse("Invalid lockout ID", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	currentUser := c.MustGet("currentUser").(user.User)
+
+	lockout, err := h.loginGuardService.ClearLockout(id, currentUser.ID)
+	if err != nil {
+		response := helper.APIResponse("Lockout not found", http.StatusNotFound, "error", nil)
+		c.JSON(http.StatusNotFound, response)
+		return
+	}
+
+	response := helper.APIResponse("Lockout cleared", http.StatusOK, "success", loginguard.FormatLockout(lockout))
+	c.JSON(http.StatusOK, response)
+}
@@ -1,21 +1,24 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"footballteam/helper"
 	"footballteam/match"
+	"footballteam/team"
 
 	"github.com/gin-gonic/gin"
 )
 
 type matchHandler struct {
 	matchService match.Service
+	logoURLs     team.URLResolver
 }
 
-func NewMatchHandler(matchService match.Service) *matchHandler {
-	return &matchHandler{matchService}
+func NewMatchHandler(matchService match.Service, logoURLs team.URLResolver) *matchHandler {
+	return &matchHandler{matchService, logoURLs}
 }
 
 // GET /matches
@@ -27,7 +30,7 @@ func (h *matchHandler) GetMatches(c *gin.Context) {
 		return
 	}
 
-	response := helper.APIResponse("List of matches", http.StatusOK, "success", match.FormatMatches(matches))
+	response := helper.APIResponse("List of matches", http.StatusOK, "success", match.FormatMatches(matches, h.logoURLs))
 	c.JSON(http.StatusOK, response)
 }
 
@@ -43,7 +46,7 @@ func (h *matchHandler) GetMatchByID(c *gin.Context) {
 		return
 	}
 
-	response := helper.APIResponse("Match detail", http.StatusOK, "success", match.FormatMatch(m))
+	response := helper.APIResponse("Match detail", http.StatusOK, "success", match.FormatMatch(m, h.logoURLs))
 	c.JSON(http.StatusOK, response)
 }
 
@@ -56,14 +59,21 @@ func (h *matchHandler) CreateMatch(c *gin.Context) {
 		return
 	}
 
+	input.Actor = currentActor(c)
+
 	newMatch, err := h.matchService.CreateMatch(input)
+	if errors.Is(err, match.ErrVenueClash) {
+		response := helper.APIResponse("Failed to create match", http.StatusConflict, "error", err.Error())
+		c.JSON(http.StatusConflict, response)
+		return
+	}
 	if err != nil {
 		response := helper.APIResponse("Failed to create match", http.StatusBadRequest, "error", err.Error())
 		c.JSON(http.StatusBadRequest, response)
 		return
 	}
 
-	response := helper.APIResponse("Match created successfully", http.StatusOK, "success", match.FormatMatch(newMatch))
+	response := helper.APIResponse("Match created successfully", http.StatusOK, "success", match.FormatMatch(newMatch, h.logoURLs))
 	c.JSON(http.StatusOK, response)
 }
 
@@ -79,14 +89,26 @@ func (h *matchHandler) UpdateMatch(c *gin.Context) {
 		return
 	}
 
+	input.Actor = currentActor(c)
+
 	updatedMatch, err := h.matchService.UpdateMatch(id, input)
+	if errors.Is(err, match.ErrVenueClash) {
+		response := helper.APIResponse("Failed to update match", http.StatusConflict, "error", err.Error())
+		c.JSON(http.StatusConflict, response)
+		return
+	}
+	if errors.Is(err, match.ErrVenueNotFound) || errors.Is(err, match.ErrInvalidSchedule) {
+		response := helper.APIResponse("Failed to update match", http.StatusUnprocessableEntity, "error", err.Error())
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
 	if err != nil {
 		response := helper.APIResponse("Failed to update match", http.StatusInternalServerError, "error", err.Error())
 		c.JSON(http.StatusInternalServerError, response)
 		return
 	}
 
-	response := helper.APIResponse("Match updated successfully", http.StatusOK, "success", match.FormatMatch(updatedMatch))
+	response := helper.APIResponse("Match updated successfully", http.StatusOK, "success", match.FormatMatch(updatedMatch, h.logoURLs))
 	c.JSON(http.StatusOK, response)
 }
 
@@ -95,7 +117,10 @@ func (h *matchHandler) DeleteMatch(c *gin.Context) {
 	idParam := c.Param("id")
 	id, _ := strconv.Atoi(idParam)
 
-	err := h.matchService.DeleteMatch(id)
+	err := h.matchService.DeleteMatch(id, currentActor(c))
+	if respondInUse(c, "Failed to delete match", err) {
+		return
+	}
 	if err != nil {
 		response := helper.APIResponse("Failed to delete match", http.StatusInternalServerError, "error", err.Error())
 		c.JSON(http.StatusInternalServerError, response)
@@ -105,3 +130,60 @@ func (h *matchHandler) DeleteMatch(c *gin.Context) {
 	response := helper.APIResponse("Match deleted successfully", http.StatusOK, "success", nil)
 	c.JSON(http.StatusOK, response)
 }
+
+// GET /trash/matches
+func (h *matchHandler) GetTrashedMatches(c *gin.Context) {
+	matches, err := h.matchService.GetTrashedMatches()
+	if err != nil {
+		response := helper.APIResponse("Failed to get deleted matches", http.StatusInternalServerError, "error", err.Error())
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helper.APIResponse("List of deleted matches", http.StatusOK, "success", match.FormatTrashedMatches(matches, h.logoURLs))
+	c.JSON(http.StatusOK, response)
+}
+
+// POST /matches/:id/restore
+func (h *matchHandler) RestoreMatch(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helper.APIResponse("Invalid match ID", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	restoredMatch, err := h.matchService.RestoreMatch(id, currentActor(c))
+	if err != nil {
+		status := trashErrorStatus(err)
+		response := helper.APIResponse("Failed to restore match", status, "error", err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	response := helper.APIResponse("Match restored successfully", http.StatusOK, "success", match.FormatMatch(restoredMatch, h.logoURLs))
+	c.JSON(http.StatusOK, response)
+}
+
+// DELETE /trash/matches/:id
+func (h *matchHandler) PurgeMatch(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helper.APIResponse("Invalid match ID", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if err := h.matchService.PurgeMatch(id, currentActor(c)); err != nil {
+		if respondInUse(c, "Failed to purge match", err) {
+			return
+		}
+		status := trashErrorStatus(err)
+		response := helper.APIResponse("Failed to purge match", status, "error", err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	response := helper.APIResponse("Match permanently deleted", http.StatusOK, "success", nil)
+	c.JSON(http.StatusOK, response)
+}
@@ -4,6 +4,7 @@ import (
 	"footballteam/helper"
 	"footballteam/match_result"
 	"footballteam/player"
+	"footballteam/team"
 	"net/http"
 	"strconv"
 
@@ -13,12 +14,14 @@ import (
 type matchResultHandler struct {
 	service       match_result.Service
 	playerService player.Service
+	logoURLs      team.URLResolver
 }
 
-func NewMatchResultHandler(s match_result.Service, p player.Service) *matchResultHandler {
+func NewMatchResultHandler(s match_result.Service, p player.Service, logoURLs team.URLResolver) *matchResultHandler {
 	return &matchResultHandler{
 		service:       s,
 		playerService: p,
+		logoURLs:      logoURLs,
 	}
 }
 
@@ -31,6 +34,8 @@ func (h *matchResultHandler) CreateMatchResult(c *gin.Context) {
 		return
 	}
 
+	input.Actor = currentActor(c)
+
 	result, err := h.service.Create(input)
 	if err != nil {
 		response := helper.APIResponse("Failed to create match result", http.StatusBadRequest, "error", err.Error())
@@ -122,15 +127,93 @@ func (h *matchResultHandler) GetMatchResultByID(c *gin.Context) {
 }
 
 func (h *matchResultHandler) GetMatchResultsReport(c *gin.Context) {
-	report, err := h.service.GetMatchResultsReport()
+	results, err := h.service.GetMatchResultsReport()
 	if err != nil {
 		response := helper.APIResponse("Failed to get match results report", http.StatusInternalServerError, "error", err.Error())
 		c.JSON(http.StatusInternalServerError, response)
 		return
 	}
+	report := match_result.FormatMatchResultReport(results, h.logoURLs)
 
 	response := helper.APIResponse("Match results report", http.StatusOK, "success", report)
 	c.JSON(http.StatusOK, response)
 }
 
+// DELETE /match_results/:id
+func (h *matchResultHandler) DeleteMatchResult(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helper.APIResponse("Invalid match result ID", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if err := h.service.Delete(id, currentActor(c)); err != nil {
+		status := trashErrorStatus(err)
+		response := helper.APIResponse("Failed to delete match result", status, "error", err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	response := helper.APIResponse("Match result deleted", http.StatusOK, "success", nil)
+	c.JSON(http.StatusOK, response)
+}
+
+// GET /trash/match_results
+func (h *matchResultHandler) GetTrashedMatchResults(c *gin.Context) {
+	results, err := h.service.GetTrashed()
+	if err != nil {
+		response := helper.APIResponse("Failed to get deleted match results", http.StatusInternalServerError, "error", err.Error())
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helper.APIResponse("List of deleted match results", http.StatusOK, "success", match_result.FormatTrashedMatchResults(results))
+	c.JSON(http.StatusOK, response)
+}
+
+// POST /match_results/:id/restore
+func (h *matchResultHandler) RestoreMatchResult(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helper.APIResponse("Invalid match result ID", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	result, err := h.service.Restore(id, currentActor(c))
+	if err != nil {
+		status := trashErrorStatus(err)
+		response := helper.APIResponse("Failed to restore match result", status, "error", err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	playerMap := make(map[int]string)
+	for _, g := range result.Goals {
+		playerMap[g.PlayerID] = g.Player.Name
+	}
+
+	response := helper.APIResponse("Match result restored", http.StatusOK, "success", match_result.FormatMatchResult(result, playerMap))
+	c.JSON(http.StatusOK, response)
+}
+
+// DELETE /trash/match_results/:id
+func (h *matchResultHandler) PurgeMatchResult(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helper.APIResponse("Invalid match result ID", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if err := h.service.Purge(id, currentActor(c)); err != nil {
+		status := trashErrorStatus(err)
+		response := helper.APIResponse("Failed to purge match result", status, "error", err.Error())
+		c.JSON(status, response)
+		return
+	}
 
+	response := helper.APIResponse("Match result permanently deleted", http.StatusOK, "success", nil)
+	c.JSON(http.StatusOK, response)
+}
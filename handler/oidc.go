@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"errors"
+	"footballteam/auth"
+	"footballteam/helper"
+	"footballteam/oidc"
+	"footballteam/user"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	oidcStateCookie     = "oidc_state"
+	oidcStateCookiePath = "/api/v1/sessions/oidc"
+)
+
+type oidcHandler struct {
+	oidcService oidc.Service
+	userService user.Service
+	authService auth.Service
+}
+
+func NewOIDCHandler(oidcService oidc.Service, userService user.Service, authService auth.Service) *oidcHandler {
+	return &oidcHandler{oidcService, userService, authService}
+}
+
+// GET /sessions/oidc/login
+// Redirect ke identity provider. State juga disimpan di cookie supaya
+// callback hanya diterima dari browser yang memulai login.
+func (h *oidcHandler) Login(c *gin.Context) {
+	authURL, state, err := h.oidcService.AuthCodeURL()
+	if err != nil {
+		response := helper.APIResponse("Identity provider unavailable", http.StatusBadGateway, "error", nil)
+		c.JSON(http.StatusBadGateway, response)
+		return
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, state, int(h.oidcService.StateTTL().Seconds()), oidcStateCookiePath, "", c.Request.TLS != nil, true)
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// GET /sessions/oidc/callback?code=...&state=...
+func (h *oidcHandler) Callback(c *gin.Context) {
+	if providerError := c.Query("error"); providerError != "" {
+		response := helper.APIResponse("Login failed", http.StatusUnauthorized, "error", gin.H{"error_code": "oidc_" + providerError})
+		c.JSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	state := c.Query("state")
+	cookieState, _ := c.Cookie(oidcStateCookie)
+	c.SetCookie(oidcStateCookie, "", -1, oidcStateCookiePath, "", c.Request.TLS != nil, true)
+
+	if state == "" || subtle.ConstantTimeCompare([]byte(state), []byte(cookieState)) != 1 {
+		response := helper.APIResponse("Login failed", http.StatusUnauthorized, "error", gin.H{"error_code": "invalid_state"})
+		c.JSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	claims, err := h.oidcService.Exchange(state, c.Query("code"))
+	if errors.Is(err, oidc.ErrProviderUnavailable) {
+		response := helper.APIResponse("Identity provider unavailable", http.StatusBadGateway, "error", nil)
+		c.JSON(http.StatusBadGateway, response)
+		return
+	}
+	if err != nil {
+		code := "invalid_id_token"
+		switch {
+		case errors.Is(err, oidc.ErrInvalidState):
+			code = "invalid_state"
+		case errors.Is(err, oidc.ErrCodeExchange):
+			code = "code_exchange_failed"
+		}
+
+		response := helper.APIResponse("Login failed", http.StatusUnauthorized, "error", gin.H{"error_code": code})
+		c.JSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	loggedinUser, err := h.userService.LoginWithOIDC(user.OIDCLoginInput{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Name:          claims.Name,
+	})
+	if errors.Is(err, user.ErrUserDeactivated) {
+		response := helper.APIResponse("Account deactivated", http.StatusForbidden, "error", gin.H{"error_code": "account_deactivated"})
+		c.JSON(http.StatusForbidden, response)
+		return
+	}
+	if errors.Is(err, user.ErrOIDCEmailUnverified) || errors.Is(err, user.ErrOIDCAccountConflict) {
+		response := helper.APIResponse("Login failed", http.StatusForbidden, "error", gin.H{"errors": err.Error()})
+		c.JSON(http.StatusForbidden, response)
+		return
+	}
+	if err != nil {
+		response := helper.APIResponse("Login failed", http.StatusInternalServerError, "error", nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// Login lewat identity provider tidak menggantikan 2FA lokal
+	respondLogin(c, h.authService, loggedinUser)
+}
@@ -1,13 +1,16 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 
 	"footballteam/helper"
 	"footballteam/player"
+	"footballteam/user"
 )
 
 type playerHandler struct {
@@ -18,15 +21,52 @@ func NewPlayerHandler(playerService player.Service) *playerHandler {
 	return &playerHandler{playerService}
 }
 
+// playerErrorStatus memetakan error create, update, dan transfer pemain ke
+// status HTTP.
+func playerErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, user.ErrTeamAccessDenied):
+		return http.StatusForbidden
+	case errors.Is(err, player.ErrNumberTaken):
+		return http.StatusConflict
+	case errors.Is(err, player.ErrTeamChangeNotAllowed),
+		errors.Is(err, player.ErrSameTeam),
+		errors.Is(err, player.ErrTeamNotFound),
+		errors.Is(err, player.ErrInvalidTransferDate),
+		errors.Is(err, player.ErrOutsideTransferWindow):
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusInternalServerError
+}
+
+// GET /players?q=rizky&team_id=1&position=FW&number_from=1&number_to=11&age_from=18&sort=-height&page=1&per_page=20
 func (h *playerHandler) GetPlayers(c *gin.Context) {
-	players, err := h.playerService.GetAllPlayers()
+	var input player.ListPlayersInput
+	if err := c.ShouldBindQuery(&input); err != nil {
+		response := helper.APIResponse("Invalid player filter", http.StatusUnprocessableEntity, "error", helper.FormatValidationError(err))
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	h.listPlayers(c, input, "List of players")
+}
+
+// listPlayers dipakai GET /players dan GET /players/team/:team_id supaya
+// keduanya memakai filter, sort, dan paging yang sama.
+func (h *playerHandler) listPlayers(c *gin.Context, input player.ListPlayersInput, message string) {
+	input.Normalize()
+
+	players, total, err := h.playerService.GetAllPlayers(input)
 	if err != nil {
 		response := helper.APIResponse("Failed to get players", http.StatusInternalServerError, "error", err.Error())
 		c.JSON(http.StatusInternalServerError, response)
 		return
 	}
 
-	response := helper.APIResponse("List of players", http.StatusOK, "success", player.FormatPlayers(players))
+	pagination := helper.NewPagination(input.PageInput, total, c.Request.URL)
+	response := helper.APIResponseWithPagination(message, http.StatusOK, "success", player.FormatPlayers(players, requestLanguage(c)), pagination)
 	c.JSON(http.StatusOK, response)
 }
 
@@ -41,23 +81,32 @@ func (h *playerHandler) GetPlayerByID(c *gin.Context) {
 		return
 	}
 
-	response := helper.APIResponse("Player detail", http.StatusOK, "success", player.FormatPlayer(p))
+	response := helper.APIResponse("Player detail", http.StatusOK, "success", player.FormatPlayer(p, requestLanguage(c)))
 	c.JSON(http.StatusOK, response)
 }
 
+// GET /players/team/:team_id menerima query yang sama dengan GET /players;
+// team_id dari path selalu dipakai dan urutan default-nya nomor punggung.
 func (h *playerHandler) GetPlayersByTeam(c *gin.Context) {
-	teamIDParam := c.Param("team_id")
-	teamID, _ := strconv.Atoi(teamIDParam)
+	teamID, err := strconv.Atoi(c.Param("team_id"))
+	if err != nil || teamID < 1 {
+		response := helper.APIResponse("Invalid team ID", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
 
-	players, err := h.playerService.GetPlayersByTeam(teamID)
-	if err != nil {
-		response := helper.APIResponse("Failed to get players by team", http.StatusInternalServerError, "error", err.Error())
-		c.JSON(http.StatusInternalServerError, response)
+	var input player.ListPlayersInput
+	if err := c.ShouldBindQuery(&input); err != nil {
+		response := helper.APIResponse("Invalid player filter", http.StatusUnprocessableEntity, "error", helper.FormatValidationError(err))
+		c.JSON(http.StatusUnprocessableEntity, response)
 		return
 	}
+	input.TeamID = teamID
+	if input.Sort == "" {
+		input.Sort = "number"
+	}
 
-	response := helper.APIResponse("Players by team", http.StatusOK, "success", player.FormatPlayers(players))
-	c.JSON(http.StatusOK, response)
+	h.listPlayers(c, input, "Players by team")
 }
 
 func (h *playerHandler) CreatePlayer(c *gin.Context) {
@@ -68,14 +117,17 @@ func (h *playerHandler) CreatePlayer(c *gin.Context) {
 		return
 	}
 
+	input.User = c.MustGet("currentUser").(user.User)
+
 	newPlayer, err := h.playerService.CreatePlayer(input)
 	if err != nil {
-		response := helper.APIResponse("Failed to create player", http.StatusInternalServerError, "error", err.Error())
-		c.JSON(http.StatusInternalServerError, response)
+		status := playerErrorStatus(err)
+		response := helper.APIResponse("Failed to create player", status, "error", err.Error())
+		c.JSON(status, response)
 		return
 	}
 
-	response := helper.APIResponse("Player created successfully", http.StatusCreated, "success", player.FormatPlayer(newPlayer))
+	response := helper.APIResponse("Player created successfully", http.StatusCreated, "success", player.FormatPlayer(newPlayer, requestLanguage(c)))
 	c.JSON(http.StatusCreated, response)
 }
 
@@ -90,14 +142,17 @@ func (h *playerHandler) UpdatePlayer(c *gin.Context) {
 		return
 	}
 
+	input.User = c.MustGet("currentUser").(user.User)
+
 	updatedPlayer, err := h.playerService.UpdatePlayer(id, input)
 	if err != nil {
-		response := helper.APIResponse("Failed to update player", http.StatusInternalServerError, "error", err.Error())
-		c.JSON(http.StatusInternalServerError, response)
+		status := playerErrorStatus(err)
+		response := helper.APIResponse("Failed to update player", status, "error", err.Error())
+		c.JSON(status, response)
 		return
 	}
 
-	response := helper.APIResponse("Player updated successfully", http.StatusOK, "success", player.FormatPlayer(updatedPlayer))
+	response := helper.APIResponse("Player updated successfully", http.StatusOK, "success", player.FormatPlayer(updatedPlayer, requestLanguage(c)))
 	c.JSON(http.StatusOK, response)
 }
 
@@ -105,7 +160,14 @@ func (h *playerHandler) DeletePlayer(c *gin.Context) {
 	idParam := c.Param("id")
 	id, _ := strconv.Atoi(idParam)
 
-	err := h.playerService.DeletePlayer(id)
+	currentUser := c.MustGet("currentUser").(user.User)
+
+	err := h.playerService.DeletePlayer(id, currentUser)
+	if errors.Is(err, user.ErrTeamAccessDenied) {
+		response := helper.APIResponse("Failed to delete player", http.StatusForbidden, "error", err.Error())
+		c.JSON(http.StatusForbidden, response)
+		return
+	}
 	if err != nil {
 		response := helper.APIResponse("Failed to delete player", http.StatusInternalServerError, "error", err.Error())
 		c.JSON(http.StatusInternalServerError, response)
@@ -115,3 +177,117 @@ func (h *playerHandler) DeletePlayer(c *gin.Context) {
 	response := helper.APIResponse("Player deleted successfully", http.StatusOK, "success", nil)
 	c.JSON(http.StatusOK, response)
 }
+
+// POST /players/:id/transfers
+func (h *playerHandler) TransferPlayer(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helper.APIResponse("Invalid player ID", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	var input player.TransferPlayerInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response := helper.APIResponse("Invalid input", http.StatusBadRequest, "error", helper.FormatValidationError(err))
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	input.User = c.MustGet("currentUser").(user.User)
+
+	transfer, err := h.playerService.TransferPlayer(id, input)
+	if err != nil {
+		status := playerErrorStatus(err)
+		response := helper.APIResponse("Failed to transfer player", status, "error", err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	response := helper.APIResponse("Player transferred successfully", http.StatusCreated, "success", player.FormatTransfer(transfer))
+	c.JSON(http.StatusCreated, response)
+}
+
+// GET /players/:id/transfers
+func (h *playerHandler) GetTransfers(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helper.APIResponse("Invalid player ID", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	transfers, err := h.playerService.GetTransfers(id)
+	if err != nil {
+		status := playerErrorStatus(err)
+		response := helper.APIResponse("Failed to get transfers", status, "error", err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	response := helper.APIResponse("Player transfer history", http.StatusOK, "success", player.FormatTransfers(transfers))
+	c.JSON(http.StatusOK, response)
+}
+
+// GET /trash/players
+func (h *playerHandler) GetTrashedPlayers(c *gin.Context) {
+	currentUser := c.MustGet("currentUser").(user.User)
+
+	players, err := h.playerService.GetTrashedPlayers(currentUser)
+	if err != nil {
+		response := helper.APIResponse("Failed to get deleted players", http.StatusInternalServerError, "error", err.Error())
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helper.APIResponse("List of deleted players", http.StatusOK, "success", player.FormatTrashedPlayers(players, requestLanguage(c)))
+	c.JSON(http.StatusOK, response)
+}
+
+// POST /players/:id/restore
+func (h *playerHandler) RestorePlayer(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helper.APIResponse("Invalid player ID", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	currentUser := c.MustGet("currentUser").(user.User)
+
+	restoredPlayer, err := h.playerService.RestorePlayer(id, currentUser)
+	if err != nil {
+		status := trashErrorStatus(err)
+		response := helper.APIResponse("Failed to restore player", status, "error", err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	response := helper.APIResponse("Player restored successfully", http.StatusOK, "success", player.FormatPlayer(restoredPlayer, requestLanguage(c)))
+	c.JSON(http.StatusOK, response)
+}
+
+// DELETE /trash/players/:id
+func (h *playerHandler) PurgePlayer(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helper.APIResponse("Invalid player ID", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	currentUser := c.MustGet("currentUser").(user.User)
+
+	if err := h.playerService.PurgePlayer(id, currentUser); err != nil {
+		if respondInUse(c, "Failed to purge player", err) {
+			return
+		}
+		status := trashErrorStatus(err)
+		response := helper.APIResponse("Failed to purge player", status, "error", err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	response := helper.APIResponse("Player permanently deleted", http.StatusOK, "success", nil)
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"footballteam/player"
+	"footballteam/user"
+
+	"gorm.io/gorm"
+)
+
+func TestPlayerErrorStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{gorm.ErrRecordNotFound, http.StatusNotFound},
+		{user.ErrTeamAccessDenied, http.StatusForbidden},
+		{player.ErrNumberTaken, http.StatusConflict},
+		{player.ErrTeamNotFound, http.StatusUnprocessableEntity},
+		{player.ErrSameTeam, http.StatusUnprocessableEntity},
+		{player.ErrInvalidTransferDate, http.StatusUnprocessableEntity},
+		{fmt.Errorf("%w [07-01:08-31]", player.ErrOutsideTransferWindow), http.StatusUnprocessableEntity},
+		{errors.New("connection refused"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		if got := playerErrorStatus(tt.err); got != tt.want {
+			t.Errorf("playerErrorStatus(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
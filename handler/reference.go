@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"footballteam/helper"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondInUse membalas 409 beserta daftar blocker jika err adalah
+// *helper.InUseError. Mengembalikan false jika err bukan error tersebut.
+func respondInUse(c *gin.Context, message string, err error) bool {
+	var inUse *helper.InUseError
+	if !errors.As(err, &inUse) {
+		return false
+	}
+
+	response := helper.APIResponse(message, http.StatusConflict, "error", gin.H{
+		"errors":   err.Error(),
+		"blockers": inUse.Blockers,
+	})
+	c.JSON(http.StatusConflict, response)
+	return true
+}
@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"footballteam/helper"
+	"footballteam/staff"
+	"footballteam/user"
+)
+
+type staffHandler struct {
+	staffService staff.Service
+}
+
+func NewStaffHandler(staffService staff.Service) *staffHandler {
+	return &staffHandler{staffService}
+}
+
+// staffErrorStatus memetakan error create/update/delete staf ke status HTTP.
+func staffErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, user.ErrTeamAccessDenied):
+		return http.StatusForbidden
+	case errors.Is(err, staff.ErrTeamNotFound), errors.Is(err, staff.ErrInvalidDates):
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusInternalServerError
+}
+
+func (h *staffHandler) GetStaff(c *gin.Context) {
+	staffList, err := h.staffService.GetAllStaff()
+	if err != nil {
+		response := helper.APIResponse("Failed to get staff", http.StatusInternalServerError, "error", err.Error())
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helper.APIResponse("List of staff", http.StatusOK, "success", staff.FormatStaffList(staffList))
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *staffHandler) GetStaffByID(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+
+	s, err := h.staffService.GetStaffByID(id)
+	if err != nil {
+		response := helper.APIResponse("Staff not found", http.StatusNotFound, "error", err.Error())
+		c.JSON(http.StatusNotFound, response)
+		return
+	}
+
+	response := helper.APIResponse("Staff detail", http.StatusOK, "success", staff.FormatStaff(s))
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *staffHandler) GetStaffByTeam(c *gin.Context) {
+	teamID, _ := strconv.Atoi(c.Param("team_id"))
+
+	staffList, err := h.staffService.GetStaffByTeam(teamID)
+	if err != nil {
+		response := helper.APIResponse("Failed to get staff by team", http.StatusInternalServerError, "error", err.Error())
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helper.APIResponse("Staff by team", http.StatusOK, "success", staff.FormatStaffList(staffList))
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *staffHandler) CreateStaff(c *gin.Context) {
+	var input staff.CreateStaffInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response := helper.APIResponse("Invalid input", http.StatusUnprocessableEntity, "error", helper.FormatValidationError(err))
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	input.User = c.MustGet("currentUser").(user.User)
+
+	newStaff, err := h.staffService.CreateStaff(input)
+	if err != nil {
+		status := staffErrorStatus(err)
+		response := helper.APIResponse("Failed to create staff", status, "error", err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	response := helper.APIResponse("Staff created successfully", http.StatusCreated, "success", staff.FormatStaff(newStaff))
+	c.JSON(http.StatusCreated, response)
+}
+
+func (h *staffHandler) UpdateStaff(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helper.APIResponse("Invalid staff ID", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	var input staff.UpdateStaffInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response := helper.APIResponse("Invalid input", http.StatusUnprocessableEntity, "error", helper.FormatValidationError(err))
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	input.User = c.MustGet("currentUser").(user.User)
+
+	updatedStaff, err := h.staffService.UpdateStaff(id, input)
+	if err != nil {
+		status := staffErrorStatus(err)
+		response := helper.APIResponse("Failed to update staff", status, "error", err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	response := helper.APIResponse("Staff updated successfully", http.StatusOK, "success", staff.FormatStaff(updatedStaff))
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *staffHandler) DeleteStaff(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helper.APIResponse("Invalid staff ID", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	currentUser := c.MustGet("currentUser").(user.User)
+
+	if err := h.staffService.DeleteStaff(id, currentUser); err != nil {
+		status := staffErrorStatus(err)
+		response := helper.APIResponse("Failed to delete staff", status, "error", err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	response := helper.APIResponse("Staff deleted successfully", http.StatusOK, "success", nil)
+	c.JSON(http.StatusOK, response)
+}
+
+// GET /trash/staff
+func (h *staffHandler) GetTrashedStaff(c *gin.Context) {
+	currentUser := c.MustGet("currentUser").(user.User)
+
+	staffList, err := h.staffService.GetTrashedStaff(currentUser)
+	if err != nil {
+		response := helper.APIResponse("Failed to get deleted staff", http.StatusInternalServerError, "error", err.Error())
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helper.APIResponse("List of deleted staff", http.StatusOK, "success", staff.FormatTrashedStaff(staffList))
+	c.JSON(http.StatusOK, response)
+}
+
+// POST /staff/:id/restore
+func (h *staffHandler) RestoreStaff(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helper.APIResponse("Invalid staff ID", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	currentUser := c.MustGet("currentUser").(user.User)
+
+	restoredStaff, err := h.staffService.RestoreStaff(id, currentUser)
+	if err != nil {
+		status := trashErrorStatus(err)
+		response := helper.APIResponse("Failed to restore staff", status, "error", err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	response := helper.APIResponse("Staff restored successfully", http.StatusOK, "success", staff.FormatStaff(restoredStaff))
+	c.JSON(http.StatusOK, response)
+}
+
+// DELETE /trash/staff/:id
+func (h *staffHandler) PurgeStaff(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helper.APIResponse("Invalid staff ID", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	currentUser := c.MustGet("currentUser").(user.User)
+
+	if err := h.staffService.PurgeStaff(id, currentUser); err != nil {
+		status := trashErrorStatus(err)
+		response := helper.APIResponse("Failed to purge staff", status, "error", err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	response := helper.APIResponse("Staff permanently deleted", http.StatusOK, "success", nil)
+	c.JSON(http.StatusOK, response)
+}
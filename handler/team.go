@@ -1,56 +1,173 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"footballteam/helper"
+	"footballteam/match"
+	"footballteam/match_result"
+	"footballteam/media"
+	"footballteam/player"
+	"footballteam/staff"
 	"footballteam/team"
+	"footballteam/user"
 	"net/http"
-	"os"
-	"path/filepath"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultTeamDetailLimit = 5
+	maxTeamDetailLimit     = 20
 )
 
 type teamHandler struct {
-	teamService team.Service
+	teamService        team.Service
+	playerService      player.Service
+	staffService       staff.Service
+	matchService       match.Service
+	matchResultService match_result.Service
+	logoURLs           team.URLResolver
+	maxLogoSize        int64
 }
 
-func NewTeamHandler(teamService team.Service) *teamHandler {
-	return &teamHandler{teamService}
+// logoFormOverhead adalah ruang untuk header dan boundary multipart di atas
+// ukuran file logo itu sendiri.
+const logoFormOverhead = 64 << 10
+
+func NewTeamHandler(teamService team.Service, playerService player.Service, staffService staff.Service, matchService match.Service, matchResultService match_result.Service, logoURLs team.URLResolver, maxLogoSize int64) *teamHandler {
+	if maxLogoSize <= 0 {
+		maxLogoSize = media.DefaultMaxSize
+	}
+	return &teamHandler{teamService, playerService, staffService, matchService, matchResultService, logoURLs, maxLogoSize}
 }
 
-// GET /api/teams
+// teamDetailFormatter adalah TeamFormatter ditambah bagian yang diminta lewat
+// ?include=; bagian yang tidak diminta tidak ikut di respon.
+type teamDetailFormatter struct {
+	team.TeamFormatter
+	Players  *[]player.PlayerFormatter           `json:"players,omitempty"`
+	Staff    *[]staff.StaffFormatter             `json:"staff,omitempty"`
+	Upcoming *[]match.MatchFormatter             `json:"upcoming,omitempty"`
+	Recent   *[]match_result.TeamResultFormatter `json:"recent,omitempty"`
+	Form     *string                             `json:"form,omitempty"`
+}
+
+// GET /api/teams?page=1&per_page=20&q=garuda&city=Bandung&year_from=1990&year_to=2000&sort=-year_founded
 func (h *teamHandler) GetTeams(c *gin.Context) {
-	teams, err := h.teamService.GetAllTeams()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, helper.APIResponse("Failed to get teams", http.StatusInternalServerError, "error", nil))
+	var input team.ListTeamsInput
+	if err := c.ShouldBindQuery(&input); err != nil {
+		errors := helper.FormatValidationError(err)
+		c.JSON(http.StatusUnprocessableEntity, helper.APIResponse("Invalid team filter", http.StatusUnprocessableEntity, "error", errors))
 		return
 	}
+	input.Normalize()
 
-	var formattedTeams []team.TeamFormatter
-	for _, t := range teams {
-		formattedTeams = append(formattedTeams, team.FormatTeam(t))
+	teams, total, err := h.teamService.GetAllTeams(input)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, helper.APIResponse("Failed to get teams", http.StatusInternalServerError, "error", nil))
+		return
 	}
 
-	response := helper.APIResponse("List of teams", http.StatusOK, "success", formattedTeams)
+	pagination := helper.NewPagination(input.PageInput, total, c.Request.URL)
+	response := helper.APIResponseWithPagination("List of teams", http.StatusOK, "success", team.FormatTeams(teams, h.logoURLs), pagination)
 	c.JSON(http.StatusOK, response)
 }
 
-// GET /api/teams/:id
+// GET /api/teams/:id?include=players,upcoming,recent,form&limit=5
+// GetTeamByID menerima ID numerik maupun slug, misalnya /teams/12 atau
+// /teams/garuda-fc.
 func (h *teamHandler) GetTeamByID(c *gin.Context) {
 	idParam := c.Param("id")
-	id, _ := strconv.Atoi(idParam)
 
-	t, err := h.teamService.GetTeamByID(id)
+	includes := map[string]bool{}
+	if include := c.Query("include"); include != "" {
+		for _, part := range strings.Split(include, ",") {
+			part = strings.TrimSpace(part)
+			switch part {
+			case "players", "staff", "upcoming", "recent", "form":
+				includes[part] = true
+			default:
+				c.JSON(http.StatusUnprocessableEntity, helper.APIResponse("Invalid include: "+part, http.StatusUnprocessableEntity, "error", nil))
+				return
+			}
+		}
+	}
+
+	limit := defaultTeamDetailLimit
+	if limitParam := c.Query("limit"); limitParam != "" {
+		parsed, err := strconv.Atoi(limitParam)
+		if err != nil || parsed < 1 || parsed > maxTeamDetailLimit {
+			c.JSON(http.StatusUnprocessableEntity, helper.APIResponse(fmt.Sprintf("limit must be between 1 and %d", maxTeamDetailLimit), http.StatusUnprocessableEntity, "error", nil))
+			return
+		}
+		limit = parsed
+	}
+
+	var t team.Team
+	var err error
+	if id, convErr := strconv.Atoi(idParam); convErr == nil {
+		t, err = h.teamService.GetTeamByID(id)
+	} else {
+		t, err = h.teamService.GetTeamBySlug(idParam)
+	}
 	if err != nil {
 		c.JSON(http.StatusNotFound, helper.APIResponse("Team not found", http.StatusNotFound, "error", nil))
 		return
 	}
 
-	response := helper.APIResponse("Team detail", http.StatusOK, "success", team.FormatTeam(t))
+	detail := teamDetailFormatter{TeamFormatter: team.FormatTeam(t, h.logoURLs)}
+
+	if includes["players"] {
+		players, err := h.playerService.GetPlayersByTeam(t.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, helper.APIResponse("Failed to get team players", http.StatusInternalServerError, "error", nil))
+			return
+		}
+		formatted := player.FormatPlayers(players, requestLanguage(c))
+		detail.Players = &formatted
+	}
+
+	if includes["staff"] {
+		staffList, err := h.staffService.GetActiveStaffByTeam(t.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, helper.APIResponse("Failed to get team staff", http.StatusInternalServerError, "error", nil))
+			return
+		}
+		formatted := staff.FormatStaffList(staffList)
+		detail.Staff = &formatted
+	}
+
+	if includes["upcoming"] {
+		matches, err := h.matchService.GetUpcomingByTeam(t.ID, limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, helper.APIResponse("Failed to get upcoming matches", http.StatusInternalServerError, "error", nil))
+			return
+		}
+		formatted := match.FormatMatches(matches, h.logoURLs)
+		detail.Upcoming = &formatted
+	}
+
+	if includes["recent"] || includes["form"] {
+		results, err := h.matchResultService.GetRecentByTeam(t.ID, limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, helper.APIResponse("Failed to get recent results", http.StatusInternalServerError, "error", nil))
+			return
+		}
+		if includes["recent"] {
+			formatted := match_result.FormatTeamResults(results, t.ID, h.logoURLs)
+			detail.Recent = &formatted
+		}
+		if includes["form"] {
+			form := match_result.Form(results, t.ID)
+			detail.Form = &form
+		}
+	}
+
+	response := helper.APIResponse("Team detail", http.StatusOK, "success", detail)
 	c.JSON(http.StatusOK, response)
 }
 
@@ -63,13 +180,23 @@ func (h *teamHandler) CreateTeam(c *gin.Context) {
 		return
 	}
 
+	input.User = c.MustGet("currentUser").(user.User)
+
 	newTeam, err := h.teamService.CreateTeam(input)
+	if errors.Is(err, team.ErrNameTaken) {
+		c.JSON(http.StatusConflict, helper.APIResponse("Create team failed", http.StatusConflict, "error", err.Error()))
+		return
+	}
+	if errors.Is(err, team.ErrVenueNotFound) {
+		c.JSON(http.StatusUnprocessableEntity, helper.APIResponse("Create team failed", http.StatusUnprocessableEntity, "error", err.Error()))
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, helper.APIResponse("Create team failed", http.StatusInternalServerError, "error", nil))
 		return
 	}
 
-	response := helper.APIResponse("Team created", http.StatusOK, "success", team.FormatTeam(newTeam))
+	response := helper.APIResponse("Team created", http.StatusOK, "success", team.FormatTeam(newTeam, h.logoURLs))
 	c.JSON(http.StatusOK, response)
 }
 
@@ -91,13 +218,45 @@ func (h *teamHandler) UpdateTeam(c *gin.Context) {
 		return
 	}
 
+	input.User = c.MustGet("currentUser").(user.User)
+
 	updatedTeam, err := h.teamService.UpdateTeam(id, input)
+	if errors.Is(err, team.ErrNameTaken) {
+		c.JSON(http.StatusConflict, helper.APIResponse("Update team failed", http.StatusConflict, "error", err.Error()))
+		return
+	}
+	if errors.Is(err, team.ErrVenueNotFound) || errors.Is(err, team.ErrInvalidEffectiveDate) {
+		c.JSON(http.StatusUnprocessableEntity, helper.APIResponse("Update team failed", http.StatusUnprocessableEntity, "error", err.Error()))
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, helper.APIResponse("Update team failed", http.StatusInternalServerError, "error", nil))
 		return
 	}
 
-	response := helper.APIResponse("Team updated", http.StatusOK, "success", team.FormatTeam(updatedTeam))
+	response := helper.APIResponse("Team updated", http.StatusOK, "success", team.FormatTeam(updatedTeam, h.logoURLs))
+	c.JSON(http.StatusOK, response)
+}
+
+// GET /teams/:id/history
+func (h *teamHandler) GetTeamHistory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, helper.APIResponse("Invalid team ID", http.StatusBadRequest, "error", nil))
+		return
+	}
+
+	versions, err := h.teamService.GetTeamHistory(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, helper.APIResponse("Team not found", http.StatusNotFound, "error", nil))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, helper.APIResponse("Failed to get team history", http.StatusInternalServerError, "error", nil))
+		return
+	}
+
+	response := helper.APIResponse("Team history", http.StatusOK, "success", team.FormatTeamHistory(versions, h.logoURLs))
 	c.JSON(http.StatusOK, response)
 }
 
@@ -106,7 +265,25 @@ func (h *teamHandler) DeleteTeam(c *gin.Context) {
 	idParam := c.Param("id")
 	id, _ := strconv.Atoi(idParam)
 
-	err := h.teamService.DeleteTeam(id)
+	var input team.DeleteTeamInput
+	if err := c.ShouldBindQuery(&input); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, helper.APIResponse("Delete team failed", http.StatusUnprocessableEntity, "error", helper.FormatValidationError(err)))
+		return
+	}
+	input.User = c.MustGet("currentUser").(user.User)
+
+	err := h.teamService.DeleteTeam(id, input)
+	if respondInUse(c, "Delete team failed", err) {
+		return
+	}
+	if errors.Is(err, team.ErrReassignTarget) || errors.Is(err, team.ErrInvalidDeletePolicy) {
+		c.JSON(http.StatusUnprocessableEntity, helper.APIResponse("Delete team failed", http.StatusUnprocessableEntity, "error", err.Error()))
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, helper.APIResponse("Team not found", http.StatusNotFound, "error", nil))
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, helper.APIResponse("Delete team failed", http.StatusInternalServerError, "error", nil))
 		return
@@ -117,50 +294,125 @@ func (h *teamHandler) DeleteTeam(c *gin.Context) {
 }
 
 func (h *teamHandler) UploadLogo(c *gin.Context) {
+	// Body dibatasi sebelum multipart di-parse; kalau tidak, file yang terlalu
+	// besar tetap dibaca ke memori atau disk sebelum media service menolaknya
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxLogoSize+logoFormOverhead)
+
 	file, err := c.FormFile("logo")
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, helper.APIResponse("Logo upload failed", http.StatusRequestEntityTooLarge, "error", media.ErrTooLarge.Error()))
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusBadRequest, helper.APIResponse("Logo upload failed", http.StatusBadRequest, "error", err.Error()))
 		return
 	}
 
-	ext := strings.ToLower(filepath.Ext(file.Filename))
-	if ext != ".jpg" && ext != ".jpeg" && ext != ".png" {
-		c.JSON(http.StatusBadRequest, helper.APIResponse("Only JPG and PNG files are allowed", http.StatusBadRequest, "error", nil))
+	idParam := c.Param("id")
+	teamID, err := strconv.Atoi(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, helper.APIResponse("Invalid team ID", http.StatusBadRequest, "error", err.Error()))
 		return
 	}
 
-	uploadDir := "uploads/logo"
-	if _, err := os.Stat(uploadDir); os.IsNotExist(err) {
-		err = os.MkdirAll(uploadDir, os.ModePerm)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, helper.APIResponse("Failed to create upload directory", http.StatusInternalServerError, "error", err.Error()))
-			return
-		}
+	src, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, helper.APIResponse("Logo upload failed", http.StatusBadRequest, "error", err.Error()))
+		return
 	}
+	defer src.Close()
 
-	filename := fmt.Sprintf("%d_%s", time.Now().Unix(), file.Filename)
-	uploadPath := filepath.Join(uploadDir, filename)
+	currentUser := c.MustGet("currentUser").(user.User)
 
-	if err := c.SaveUploadedFile(file, uploadPath); err != nil {
+	// Tipe file dicek dari isinya di media service, ekstensi tidak dipercaya
+	updatedTeam, err := h.teamService.SaveLogo(teamID, src, currentUser)
+	if errors.Is(err, user.ErrTeamAccessDenied) {
+		c.JSON(http.StatusForbidden, helper.APIResponse("Logo upload failed", http.StatusForbidden, "error", err.Error()))
+		return
+	}
+	if errors.Is(err, media.ErrTooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, helper.APIResponse("Logo upload failed", http.StatusRequestEntityTooLarge, "error", err.Error()))
+		return
+	}
+	if errors.Is(err, media.ErrUnsupportedType) {
+		c.JSON(http.StatusUnsupportedMediaType, helper.APIResponse("Only JPG and PNG files are allowed", http.StatusUnsupportedMediaType, "error", err.Error()))
+		return
+	}
+	if errors.Is(err, media.ErrDimensionsTooLarge) {
+		c.JSON(http.StatusUnprocessableEntity, helper.APIResponse("Logo upload failed", http.StatusUnprocessableEntity, "error", err.Error()))
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, helper.APIResponse("Team not found", http.StatusNotFound, "error", nil))
+		return
+	}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, helper.APIResponse("Failed to save logo", http.StatusInternalServerError, "error", err.Error()))
 		return
 	}
 
-	idParam := c.Param("id")
-	teamID, err := strconv.Atoi(idParam)
+	formatter := team.FormatTeam(updatedTeam, h.logoURLs)
+	response := map[string]string{
+		"logo_url":           formatter.Logo,
+		"logo_medium_url":    formatter.LogoMedium,
+		"logo_thumbnail_url": formatter.LogoThumbnail,
+	}
+	c.JSON(http.StatusOK, helper.APIResponse("Logo uploaded successfully", http.StatusOK, "success", response))
+}
+
+// GET /trash/teams
+func (h *teamHandler) GetTrashedTeams(c *gin.Context) {
+	teams, err := h.teamService.GetTrashedTeams()
 	if err != nil {
-		c.JSON(http.StatusBadRequest, helper.APIResponse("Invalid team ID", http.StatusBadRequest, "error", err.Error()))
+		c.JSON(http.StatusInternalServerError, helper.APIResponse("Failed to get deleted teams", http.StatusInternalServerError, "error", nil))
 		return
 	}
 
-	_, err = h.teamService.SaveLogo(teamID, uploadPath)
+	response := helper.APIResponse("List of deleted teams", http.StatusOK, "success", team.FormatTrashedTeams(teams, h.logoURLs))
+	c.JSON(http.StatusOK, response)
+}
+
+// POST /teams/:id/restore
+func (h *teamHandler) RestoreTeam(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, helper.APIResponse("Failed to save logo to database", http.StatusInternalServerError, "error", err.Error()))
+		c.JSON(http.StatusBadRequest, helper.APIResponse("Invalid team ID", http.StatusBadRequest, "error", nil))
 		return
 	}
 
-	response := map[string]string{
-		"logo_url": "/" + uploadPath,
+	currentUser := c.MustGet("currentUser").(user.User)
+
+	restoredTeam, err := h.teamService.RestoreTeam(id, currentUser)
+	if err != nil {
+		status := trashErrorStatus(err)
+		c.JSON(status, helper.APIResponse("Restore team failed", status, "error", err.Error()))
+		return
 	}
-	c.JSON(http.StatusOK, helper.APIResponse("Logo uploaded successfully", http.StatusOK, "success", response))
+
+	response := helper.APIResponse("Team restored", http.StatusOK, "success", team.FormatTeam(restoredTeam, h.logoURLs))
+	c.JSON(http.StatusOK, response)
+}
+
+// DELETE /trash/teams/:id
+func (h *teamHandler) PurgeTeam(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, helper.APIResponse("Invalid team ID", http.StatusBadRequest, "error", nil))
+		return
+	}
+
+	currentUser := c.MustGet("currentUser").(user.User)
+
+	if err := h.teamService.PurgeTeam(id, currentUser); err != nil {
+		if respondInUse(c, "Purge team failed", err) {
+			return
+		}
+		status := trashErrorStatus(err)
+		c.JSON(status, helper.APIResponse("Purge team failed", status, "error", err.Error()))
+		return
+	}
+
+	response := helper.APIResponse("Team permanently deleted", http.StatusOK, "success", nil)
+	c.JSON(http.StatusOK, response)
 }
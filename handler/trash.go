@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"gorm.io/gorm"
+
+	"footballteam/helper"
+	"footballteam/match"
+	"footballteam/match_result"
+	"footballteam/player"
+	"footballteam/staff"
+	"footballteam/team"
+	"footballteam/user"
+)
+
+// trashErrorStatus memetakan error restore/purge ke status HTTP. Restore yang
+// bentrok dengan data yang dibuat setelah penghapusan dibalas 409.
+func trashErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, user.ErrTeamAccessDenied):
+		return http.StatusForbidden
+	case errors.Is(err, team.ErrNameTaken),
+		errors.Is(err, player.ErrNumberTaken),
+		errors.Is(err, player.ErrTeamNotFound),
+		errors.Is(err, staff.ErrTeamNotFound),
+		errors.Is(err, match.ErrScheduleTaken),
+		errors.Is(err, match.ErrVenueClash),
+		errors.Is(err, match_result.ErrResultExists),
+		errors.Is(err, helper.ErrInUse):
+		return http.StatusConflict
+	case errors.Is(err, match.ErrInvalidSchedule):
+		return http.StatusUnprocessableEntity
+	}
+
+	return http.StatusInternalServerError
+}
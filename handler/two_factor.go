@@ -0,0 +1,238 @@
+package handler
+
+import (
+	"errors"
+	"footballteam/auth"
+	"footballteam/helper"
+	"footballteam/loginguard"
+	"footballteam/user"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// POST /sessions/2fa
+func (h *userHandler) VerifyTwoFactor(c *gin.Context) {
+	var input user.TwoFactorChallengeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response := helper.APIResponse("Two-factor verification failed", http.StatusUnprocessableEntity, "error", helper.FormatValidationError(err))
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	userID, err := h.authService.ValidateChallengeToken(input.ChallengeToken, auth.PurposeTwoFactor)
+	if err != nil {
+		response := helper.APIResponse("Two-factor verification failed", http.StatusUnauthorized, "error", gin.H{"error_code": "invalid_challenge_token"})
+		c.JSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	challengedUser, err := h.userService.GetUserByID(userID)
+	if err != nil {
+		response := helper.APIResponse("Two-factor verification failed", http.StatusUnauthorized, "error", gin.H{"error_code": "invalid_challenge_token"})
+		c.JSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	// Kode 6 digit mudah ditebak, jadi pakai lockout yang sama dengan password
+	retryAfter, err := h.loginGuardService.Check(challengedUser.Email, c.ClientIP())
+	if errors.Is(err, loginguard.ErrLocked) {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		response := helper.APIResponse("Two-factor verification failed", http.StatusTooManyRequests, "error", gin.H{"errors": err.Error()})
+		c.JSON(http.StatusTooManyRequests, response)
+		return
+	}
+	if err != nil {
+		response := helper.APIResponse("Two-factor verification failed", http.StatusInternalServerError, "error", nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	verifiedUser, err := h.userService.VerifyTwoFactor(userID, input.Code)
+	if errors.Is(err, user.ErrInvalidTwoFactorCode) {
+		if err := h.loginGuardService.RegisterFailure(challengedUser.Email, c.ClientIP()); err != nil {
+			response := helper.APIResponse("Two-factor verification failed", http.StatusInternalServerError, "error", nil)
+			c.JSON(http.StatusInternalServerError, response)
+			return
+		}
+	}
+	if err != nil {
+		response := helper.APIResponse("Two-factor verification failed", http.StatusUnprocessableEntity, "error", gin.H{"errors": err.Error()})
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	if err := h.loginGuardService.RegisterSuccess(verifiedUser.Email); err != nil {
+		response := helper.APIResponse("Two-factor verification failed", http.StatusInternalServerError, "error", nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	formatter, err := h.issueTokens(verifiedUser)
+	if err != nil {
+		response := helper.APIResponse("Two-factor verification failed", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response := helper.APIResponse("Successfuly loggedin", http.StatusOK, "success", formatter)
+	c.JSON(http.StatusOK, response)
+}
+
+// POST /sessions/2fa/enroll
+// Dipakai user yang wajib 2FA tapi belum pernah enroll
+func (h *userHandler) BeginLoginTwoFactorEnrollment(c *gin.Context) {
+	var input user.TwoFactorEnrollInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response := helper.APIResponse("Two-factor enrollment failed", http.StatusUnprocessableEntity, "error", helper.FormatValidationError(err))
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	userID, err := h.authService.ValidateChallengeToken(input.ChallengeToken, auth.PurposeTwoFactorEnroll)
+	if err != nil {
+		response := helper.APIResponse("Two-factor enrollment failed", http.StatusUnauthorized, "error", gin.H{"error_code": "invalid_challenge_token"})
+		c.JSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	h.beginTwoFactorEnrollment(c, userID)
+}
+
+// POST /sessions/2fa/confirm
+func (h *userHandler) ConfirmLoginTwoFactorEnrollment(c *gin.Context) {
+	var input user.TwoFactorChallengeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response := helper.APIResponse("Two-factor enrollment failed", http.StatusUnprocessableEntity, "error", helper.FormatValidationError(err))
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	userID, err := h.authService.ValidateChallengeToken(input.ChallengeToken, auth.PurposeTwoFactorEnroll)
+	if err != nil {
+		response := helper.APIResponse("Two-factor enrollment failed", http.StatusUnauthorized, "error", gin.H{"error_code": "invalid_challenge_token"})
+		c.JSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	codes, err := h.userService.ConfirmTwoFactorEnrollment(userID, input.Code)
+	if err != nil {
+		response := helper.APIResponse("Two-factor enrollment failed", http.StatusUnprocessableEntity, "error", gin.H{"errors": err.Error()})
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	enrolledUser, err := h.userService.GetUserByID(userID)
+	if err != nil {
+		response := helper.APIResponse("Two-factor enrollment failed", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	formatter, err := h.issueTokens(enrolledUser)
+	if err != nil {
+		response := helper.APIResponse("Two-factor enrollment failed", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	formatter.RecoveryCodes = codes
+
+	response := helper.APIResponse("Two-factor authentication enabled", http.StatusOK, "success", formatter)
+	c.JSON(http.StatusOK, response)
+}
+
+// POST /me/2fa
+func (h *userHandler) BeginTwoFactorEnrollment(c *gin.Context) {
+	currentUser := c.MustGet("currentUser").(user.User)
+
+	h.beginTwoFactorEnrollment(c, currentUser.ID)
+}
+
+func (h *userHandler) beginTwoFactorEnrollment(c *gin.Context, userID int) {
+	secret, uri, err := h.userService.BeginTwoFactorEnrollment(userID)
+	if errors.Is(err, user.ErrTwoFactorAlreadyEnabled) {
+		response := helper.APIResponse("Two-factor enrollment failed", http.StatusConflict, "error", gin.H{"errors": err.Error()})
+		c.JSON(http.StatusConflict, response)
+		return
+	}
+	if err != nil {
+		response := helper.APIResponse("Two-factor enrollment failed", http.StatusInternalServerError, "error", nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helper.APIResponse("Scan the provisioning URI and confirm with a code", http.StatusOK, "success", user.FormatTwoFactorEnrollment(secret, uri))
+	c.JSON(http.StatusOK, response)
+}
+
+// POST /me/2fa/confirm
+func (h *userHandler) ConfirmTwoFactorEnrollment(c *gin.Context) {
+	var input user.TwoFactorCodeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response := helper.APIResponse("Two-factor enrollment failed", http.StatusUnprocessableEntity, "error", helper.FormatValidationError(err))
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("currentUser").(user.User)
+
+	codes, err := h.userService.ConfirmTwoFactorEnrollment(currentUser.ID, input.Code)
+	if err != nil {
+		response := helper.APIResponse("Two-factor enrollment failed", http.StatusUnprocessableEntity, "error", gin.H{"errors": err.Error()})
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	response := helper.APIResponse("Two-factor authentication enabled", http.StatusOK, "success", user.FormatRecoveryCodes(codes))
+	c.JSON(http.StatusOK, response)
+}
+
+// DELETE /me/2fa
+func (h *userHandler) DisableTwoFactor(c *gin.Context) {
+	var input user.TwoFactorCodeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response := helper.APIResponse("Disable two-factor failed", http.StatusUnprocessableEntity, "error", helper.FormatValidationError(err))
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("currentUser").(user.User)
+
+	updatedUser, err := h.userService.DisableTwoFactor(currentUser.ID, input.Code)
+	if errors.Is(err, user.ErrTwoFactorRequired) {
+		response := helper.APIResponse("Disable two-factor failed", http.StatusForbidden, "error", gin.H{"errors": err.Error()})
+		c.JSON(http.StatusForbidden, response)
+		return
+	}
+	if err != nil {
+		response := helper.APIResponse("Disable two-factor failed", http.StatusUnprocessableEntity, "error", gin.H{"errors": err.Error()})
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	response := helper.APIResponse("Two-factor authentication disabled", http.StatusOK, "success", user.FormatAccount(updatedUser))
+	c.JSON(http.StatusOK, response)
+}
+
+// DELETE /users/:id/2fa
+// Admin me-reset 2FA user yang kehilangan perangkatnya
+func (h *userHandler) ResetTwoFactor(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helper.APIResponse("Invalid user ID", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	updatedUser, err := h.userService.ResetTwoFactor(id)
+	if err != nil {
+		response := helper.APIResponse("Reset two-factor failed", http.StatusBadRequest, "error", err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response := helper.APIResponse("Two-factor authentication reset", http.StatusOK, "success", user.FormatAccount(updatedUser))
+	c.JSON(http.StatusOK, response)
+}
@@ -1,21 +1,28 @@
 package handler
 
 import (
+	"errors"
 	"footballteam/auth"
 	"footballteam/helper"
+	"footballteam/loginguard"
 	"footballteam/user"
+	"io"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt"
 )
 
 type userHandler struct {
-	userService user.Service
-	authService auth.Service
+	userService       user.Service
+	authService       auth.Service
+	loginGuardService loginguard.Service
 }
 
-func NewUserHandler(userService user.Service, authService auth.Service) *userHandler {
-	return &userHandler{userService, authService}
+func NewUserHandler(userService user.Service, authService auth.Service, loginGuardService loginguard.Service) *userHandler {
+	return &userHandler{userService, authService, loginGuardService}
 }
 
 func (h *userHandler) Login(c *gin.Context) {
@@ -31,25 +38,352 @@ func (h *userHandler) Login(c *gin.Context) {
 		return
 	}
 
+	retryAfter, err := h.loginGuardService.Check(input.Email, c.ClientIP())
+	if errors.Is(err, loginguard.ErrLocked) {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		response := helper.APIResponse("Login failed", http.StatusTooManyRequests, "error", gin.H{"errors": err.Error()})
+		c.JSON(http.StatusTooManyRequests, response)
+		return
+	}
+	if err != nil {
+		response := helper.APIResponse("Login failed", http.StatusInternalServerError, "error", nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
 	loggedinUser, err := h.userService.Login(input)
 
-	if err != nil {
+	if errors.Is(err, user.ErrInvalidCredentials) {
+		if err := h.loginGuardService.RegisterFailure(input.Email, c.ClientIP()); err != nil {
+			response := helper.APIResponse("Login failed", http.StatusInternalServerError, "error", nil)
+			c.JSON(http.StatusInternalServerError, response)
+			return
+		}
+	}
+	if errors.Is(err, user.ErrInvalidCredentials) || errors.Is(err, user.ErrUserDeactivated) {
 		errorMessage := gin.H{"errors": err.Error()}
 
 		response := helper.APIResponse("Login failed", http.StatusUnprocessableEntity, "error", errorMessage)
 		c.JSON(http.StatusUnprocessableEntity, response)
 		return
 	}
+	// Error lain (misalnya database) tidak boleh terlihat oleh caller yang
+	// belum login
+	if err != nil {
+		response := helper.APIResponse("Login failed", http.StatusInternalServerError, "error", nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// Counter yang gagal direset akan terus mendekati lockout, jadi login
+	// dianggap gagal
+	if err := h.loginGuardService.RegisterSuccess(input.Email); err != nil {
+		response := helper.APIResponse("Login failed", http.StatusInternalServerError, "error", nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	respondLogin(c, h.authService, loggedinUser)
+}
+
+// respondLogin dipakai login password maupun OIDC setelah identitas user
+// terbukti. User dengan 2FA aktif atau wajib 2FA hanya mendapat challenge
+// token; JWT baru diterbitkan setelah langkah 2FA selesai.
+func respondLogin(c *gin.Context, authService auth.Service, loggedinUser user.User) {
+	if loggedinUser.TOTPEnabled || loggedinUser.TOTPRequired {
+		step, purpose := "verify", auth.PurposeTwoFactor
+		if !loggedinUser.TOTPEnabled {
+			step, purpose = "enroll", auth.PurposeTwoFactorEnroll
+		}
+
+		challengeToken, err := authService.GenerateChallengeToken(loggedinUser.ID, purpose)
+		if err != nil {
+			response := helper.APIResponse("Login failed", http.StatusBadRequest, "error", nil)
+			c.JSON(http.StatusBadRequest, response)
+			return
+		}
+
+		formatter := user.FormatTwoFactorChallenge(step, challengeToken, auth.ChallengeTokenTTL)
+		response := helper.APIResponse("Two-factor authentication required", http.StatusAccepted, "success", formatter)
+		c.JSON(http.StatusAccepted, response)
+		return
+	}
 
-	token, err := h.authService.GenerateToken(loggedinUser.ID)
+	formatter, err := issueTokens(authService, loggedinUser)
 	if err != nil {
 		response := helper.APIResponse("Login failed", http.StatusBadRequest, "error", nil)
 		c.JSON(http.StatusBadRequest, response)
 		return
 	}
 
-	formatter := user.FormatUser(loggedinUser, token)
 	response := helper.APIResponse("Successfuly loggedin", http.StatusOK, "success", formatter)
 	c.JSON(http.StatusOK, response)
+}
+
+func (h *userHandler) issueTokens(loggedinUser user.User) (user.UserFormatter, error) {
+	return issueTokens(h.authService, loggedinUser)
+}
+
+// issueTokens menerbitkan access token dan refresh token setelah semua
+// langkah login selesai (password, 2FA, atau OIDC).
+func issueTokens(authService auth.Service, loggedinUser user.User) (user.UserFormatter, error) {
+	token, err := authService.GenerateToken(loggedinUser.ID)
+	if err != nil {
+		return user.UserFormatter{}, err
+	}
+
+	refreshToken, err := authService.GenerateRefreshToken(loggedinUser.ID)
+	if err != nil {
+		return user.UserFormatter{}, err
+	}
+
+	return user.FormatUser(loggedinUser, token, refreshToken, authService.AccessTokenTTL()), nil
+}
+
+// POST /sessions/refresh
+func (h *userHandler) RefreshSession(c *gin.Context) {
+	var input user.RefreshSessionInput
+
+	err := c.ShouldBindJSON(&input)
+	if err != nil {
+		errors := helper.FormatValidationError(err)
+		errorMessage := gin.H{"errors": errors}
+
+		response := helper.APIResponse("Refresh session failed", http.StatusUnprocessableEntity, "error", errorMessage)
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	userID, refreshToken, err := h.authService.RotateRefreshToken(input.RefreshToken)
+	if err != nil {
+		code := "invalid_refresh_token"
+		switch {
+		case errors.Is(err, auth.ErrRefreshTokenExpired):
+			code = "refresh_token_expired"
+		case errors.Is(err, auth.ErrRefreshTokenReused):
+			code = "refresh_token_reused"
+		}
+
+		response := helper.APIResponse("Refresh session failed", http.StatusUnauthorized, "error", gin.H{"error_code": code})
+		c.JSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	currentUser, err := h.userService.GetUserByID(userID)
+	if err != nil || !currentUser.IsActive() {
+		response := helper.APIResponse("Refresh session failed", http.StatusUnauthorized, "error", gin.H{"error_code": "invalid_refresh_token"})
+		c.JSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	token, err := h.authService.GenerateToken(currentUser.ID)
+	if err != nil {
+		response := helper.APIResponse("Refresh session failed", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	formatter := user.FormatUser(currentUser, token, refreshToken, h.authService.AccessTokenTTL())
+	response := helper.APIResponse("Session refreshed", http.StatusOK, "success", formatter)
+	c.JSON(http.StatusOK, response)
+}
+
+// DELETE /sessions
+func (h *userHandler) Logout(c *gin.Context) {
+	var input user.LogoutInput
+	if err := c.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+		response := helper.APIResponse("Logout failed", http.StatusUnprocessableEntity, "error", err.Error())
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("currentUser").(user.User)
+	currentToken := c.MustGet("currentToken").(*jwt.Token)
+
+	if err := h.authService.RevokeToken(currentToken); err != nil {
+		response := helper.APIResponse("Logout failed", http.StatusInternalServerError, "error", nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	if input.RefreshToken != "" {
+		err := h.authService.RevokeRefreshToken(currentUser.ID, input.RefreshToken)
+		if err != nil && !errors.Is(err, auth.ErrInvalidRefreshToken) {
+			response := helper.APIResponse("Logout failed", http.StatusInternalServerError, "error", nil)
+			c.JSON(http.StatusInternalServerError, response)
+			return
+		}
+	}
+
+	response := helper.APIResponse("Successfuly loggedout", http.StatusOK, "success", nil)
+	c.JSON(http.StatusOK, response)
+}
+
+// DELETE /sessions/all
+func (h *userHandler) LogoutEverywhere(c *gin.Context) {
+	currentUser := c.MustGet("currentUser").(user.User)
+
+	if err := h.authService.RevokeUserTokens(currentUser.ID); err != nil {
+		response := helper.APIResponse("Logout failed", http.StatusInternalServerError, "error", nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helper.APIResponse("Logged out from all sessions", http.StatusOK, "success", nil)
+	c.JSON(http.StatusOK, response)
+}
+
+// GET /users
+func (h *userHandler) GetUsers(c *gin.Context) {
+	users, err := h.userService.GetUsers()
+	if err != nil {
+		response := helper.APIResponse("Failed to get users", http.StatusInternalServerError, "error", err.Error())
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helper.APIResponse("List of users", http.StatusOK, "success", user.FormatAccounts(users))
+	c.JSON(http.StatusOK, response)
+}
+
+// POST /users
+func (h *userHandler) CreateUser(c *gin.Context) {
+	var input user.CreateUserInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response := helper.APIResponse("Create user failed", http.StatusUnprocessableEntity, "error", helper.FormatValidationError(err))
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	newUser, temporaryPassword, err := h.userService.CreateUser(input)
+	if err != nil {
+		response := helper.APIResponse("Create user failed", http.StatusBadRequest, "error", err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	formatter := user.FormatAccount(newUser)
+	formatter.TemporaryPassword = temporaryPassword
+
+	response := helper.APIResponse("User created", http.StatusCreated, "success", formatter)
+	c.JSON(http.StatusCreated, response)
+}
+
+// PUT /users/:id
+func (h *userHandler) UpdateUser(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helper.APIResponse("Invalid user ID", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	var input user.UpdateUserInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response := helper.APIResponse("Update user failed", http.StatusUnprocessableEntity, "error", helper.FormatValidationError(err))
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	updatedUser, err := h.userService.UpdateUser(id, input)
+	if err != nil {
+		response := helper.APIResponse("Update user failed", http.StatusBadRequest, "error", err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response := helper.APIResponse("User updated", http.StatusOK, "success", user.FormatAccount(updatedUser))
+	c.JSON(http.StatusOK, response)
+}
+
+// DELETE /users/:id
+func (h *userHandler) DeactivateUser(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helper.APIResponse("Invalid user ID", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	currentUser := c.MustGet("currentUser").(user.User)
+
+	deactivatedUser, err := h.userService.DeactivateUser(id, currentUser)
+	if err != nil {
+		response := helper.APIResponse("Deactivate user failed", http.StatusBadRequest, "error", err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
 
+	if err := h.authService.RevokeUserTokens(deactivatedUser.ID); err != nil {
+		response := helper.APIResponse("Deactivate user failed", http.StatusInternalServerError, "error", nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helper.APIResponse("User deactivated", http.StatusOK, "success", user.FormatAccount(deactivatedUser))
+	c.JSON(http.StatusOK, response)
+}
+
+// POST /users/:id/activate
+func (h *userHandler) ActivateUser(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helper.APIResponse("Invalid user ID", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	activatedUser, err := h.userService.ActivateUser(id)
+	if err != nil {
+		response := helper.APIResponse("Activate user failed", http.StatusBadRequest, "error", err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response := helper.APIResponse("User activated", http.StatusOK, "success", user.FormatAccount(activatedUser))
+	c.JSON(http.StatusOK, response)
+}
+
+// POST /password/forgot
+func (h *userHandler) ForgotPassword(c *gin.Context) {
+	var input user.ForgotPasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response := helper.APIResponse("Forgot password failed", http.StatusUnprocessableEntity, "error", helper.FormatValidationError(err))
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	if err := h.userService.RequestPasswordReset(input); err != nil {
+		response := helper.APIResponse("Forgot password failed", http.StatusInternalServerError, "error", nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helper.APIResponse("If the email is registered, a reset link has been sent", http.StatusOK, "success", nil)
+	c.JSON(http.StatusOK, response)
+}
+
+// POST /password/reset
+func (h *userHandler) ResetPassword(c *gin.Context) {
+	var input user.ResetPasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response := helper.APIResponse("Reset password failed", http.StatusUnprocessableEntity, "error", helper.FormatValidationError(err))
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	_, err := h.userService.ResetPassword(input)
+	if errors.Is(err, user.ErrInvalidResetToken) {
+		response := helper.APIResponse("Reset password failed", http.StatusBadRequest, "error", err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	if err != nil {
+		response := helper.APIResponse("Reset password failed", http.StatusInternalServerError, "error", nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helper.APIResponse("Password has been reset", http.StatusOK, "success", nil)
+	c.JSON(http.StatusOK, response)
 }
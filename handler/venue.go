@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"footballteam/helper"
+	"footballteam/venue"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type venueHandler struct {
+	venueService venue.Service
+}
+
+func NewVenueHandler(venueService venue.Service) *venueHandler {
+	return &venueHandler{venueService}
+}
+
+// GET /venues
+func (h *venueHandler) GetVenues(c *gin.Context) {
+	venues, err := h.venueService.GetVenues()
+	if err != nil {
+		response := helper.APIResponse("Failed to get venues", http.StatusInternalServerError, "error", err.Error())
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helper.APIResponse("List of venues", http.StatusOK, "success", venue.FormatVenues(venues))
+	c.JSON(http.StatusOK, response)
+}
+
+// GET /venues/:id
+func (h *venueHandler) GetVenueByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helper.APIResponse("Invalid venue ID", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	v, err := h.venueService.GetVenueByID(id)
+	if err != nil {
+		response := helper.APIResponse("Venue not found", http.StatusNotFound, "error", nil)
+		c.JSON(http.StatusNotFound, response)
+		return
+	}
+
+	response := helper.APIResponse("Venue detail", http.StatusOK, "success", venue.FormatVenue(v))
+	c.JSON(http.StatusOK, response)
+}
+
+// POST /venues
+func (h *venueHandler) CreateVenue(c *gin.Context) {
+	var input venue.CreateVenueInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response := helper.APIResponse("Create venue failed", http.StatusUnprocessableEntity, "error", helper.FormatValidationError(err))
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	input.Actor = currentActor(c)
+
+	newVenue, err := h.venueService.CreateVenue(input)
+	if err != nil {
+		response := helper.APIResponse("Create venue failed", http.StatusInternalServerError, "error", err.Error())
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helper.APIResponse("Venue created", http.StatusOK, "success", venue.FormatVenue(newVenue))
+	c.JSON(http.StatusOK, response)
+}
+
+// PUT /venues/:id
+func (h *venueHandler) UpdateVenue(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helper.APIResponse("Invalid venue ID", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	var input venue.UpdateVenueInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response := helper.APIResponse("Update venue failed", http.StatusUnprocessableEntity, "error", helper.FormatValidationError(err))
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	input.Actor = currentActor(c)
+
+	updatedVenue, err := h.venueService.UpdateVenue(id, input)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		response := helper.APIResponse("Venue not found", http.StatusNotFound, "error", nil)
+		c.JSON(http.StatusNotFound, response)
+		return
+	}
+	if err != nil {
+		response := helper.APIResponse("Update venue failed", http.StatusInternalServerError, "error", err.Error())
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helper.APIResponse("Venue updated", http.StatusOK, "success", venue.FormatVenue(updatedVenue))
+	c.JSON(http.StatusOK, response)
+}
+
+// DELETE /venues/:id
+func (h *venueHandler) DeleteVenue(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helper.APIResponse("Invalid venue ID", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	err = h.venueService.DeleteVenue(id, currentActor(c))
+	if respondInUse(c, "Delete venue failed", err) {
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		response := helper.APIResponse("Venue not found", http.StatusNotFound, "error", nil)
+		c.JSON(http.StatusNotFound, response)
+		return
+	}
+	if err != nil {
+		response := helper.APIResponse("Delete venue failed", http.StatusInternalServerError, "error", err.Error())
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helper.APIResponse("Venue deleted", http.StatusOK, "success", nil)
+	c.JSON(http.StatusOK, response)
+}
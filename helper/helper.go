@@ -3,8 +3,9 @@ package helper
 import "github.com/go-playground/validator/v10"
 
 type Response struct {
-	Meta Meta        `json:"meta"`
-	Data interface{} `json:"data"`
+	Meta       Meta        `json:"meta"`
+	Data       interface{} `json:"data"`
+	Pagination *Pagination `json:"pagination,omitempty"`
 }
 
 type Meta struct {
@@ -28,10 +29,23 @@ func APIResponse(message string, code int, status string, data interface{}) Resp
 	return jsonResponse
 }
 
+func APIResponseWithPagination(message string, code int, status string, data interface{}, pagination Pagination) Response {
+	jsonResponse := APIResponse(message, code, status, data)
+	jsonResponse.Pagination = &pagination
+
+	return jsonResponse
+}
+
 func FormatValidationError(err error) []string {
 	var errors []string
 
-	for _, e := range err.(validator.ValidationErrors) {
+	// Error bind selain validasi (JSON rusak, query bukan angka, dll.)
+	validationErrors, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return []string{err.Error()}
+	}
+
+	for _, e := range validationErrors {
 		errors = append(errors, e.Error())
 	}
 
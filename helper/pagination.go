@@ -0,0 +1,82 @@
+package helper
+
+import (
+	"net/url"
+	"strconv"
+)
+
+const (
+	DefaultPerPage = 20
+	MaxPerPage     = 100
+)
+
+// PageInput di-embed ke input list yang dibind dari query string.
+type PageInput struct {
+	Page    int `form:"page" binding:"omitempty,min=1"`
+	PerPage int `form:"per_page" binding:"omitempty,min=1,max=100"`
+}
+
+// Normalize mengisi nilai default untuk page dan per_page yang kosong.
+func (p *PageInput) Normalize() {
+	if p.Page < 1 {
+		p.Page = 1
+	}
+	if p.PerPage < 1 {
+		p.PerPage = DefaultPerPage
+	}
+	if p.PerPage > MaxPerPage {
+		p.PerPage = MaxPerPage
+	}
+}
+
+func (p PageInput) Offset() int {
+	return (p.Page - 1) * p.PerPage
+}
+
+type Pagination struct {
+	Page       int     `json:"page"`
+	PerPage    int     `json:"per_page"`
+	Total      int64   `json:"total"`
+	TotalPages int     `json:"total_pages"`
+	Next       *string `json:"next"`
+	Prev       *string `json:"prev"`
+}
+
+// NewPagination membuat metadata pagination. Link next/prev memakai path dan
+// query request saat ini, hanya parameter page yang diganti.
+func NewPagination(page PageInput, total int64, requestURL *url.URL) Pagination {
+	totalPages := int((total + int64(page.PerPage) - 1) / int64(page.PerPage))
+
+	pagination := Pagination{
+		Page:       page.Page,
+		PerPage:    page.PerPage,
+		Total:      total,
+		TotalPages: totalPages,
+	}
+
+	if page.Page < totalPages {
+		next := pageLink(requestURL, page.Page+1)
+		pagination.Next = &next
+	}
+	if page.Page > 1 {
+		// Halaman di luar jangkauan diarahkan kembali ke halaman terakhir
+		prevPage := page.Page - 1
+		if prevPage > totalPages {
+			prevPage = totalPages
+		}
+		if prevPage >= 1 {
+			prev := pageLink(requestURL, prevPage)
+			pagination.Prev = &prev
+		}
+	}
+
+	return pagination
+}
+
+func pageLink(requestURL *url.URL, page int) string {
+	query := requestURL.Query()
+	query.Set("page", strconv.Itoa(page))
+
+	link := url.URL{Path: requestURL.Path, RawQuery: query.Encode()}
+	return link.String()
+}
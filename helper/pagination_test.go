@@ -0,0 +1,89 @@
+package helper
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestPageInputNormalize(t *testing.T) {
+	tests := []struct {
+		in   PageInput
+		want PageInput
+	}{
+		{PageInput{}, PageInput{Page: 1, PerPage: DefaultPerPage}},
+		{PageInput{Page: -3, PerPage: -1}, PageInput{Page: 1, PerPage: DefaultPerPage}},
+		{PageInput{Page: 2, PerPage: 1}, PageInput{Page: 2, PerPage: 1}},
+		{PageInput{Page: 5, PerPage: MaxPerPage}, PageInput{Page: 5, PerPage: MaxPerPage}},
+		{PageInput{Page: 5, PerPage: MaxPerPage + 1}, PageInput{Page: 5, PerPage: MaxPerPage}},
+	}
+
+	for _, tt := range tests {
+		got := tt.in
+		got.Normalize()
+		if got != tt.want {
+			t.Errorf("Normalize(%+v) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPageInputOffset(t *testing.T) {
+	tests := []struct {
+		page, perPage, want int
+	}{
+		{1, 20, 0},
+		{2, 20, 20},
+		{3, 7, 14},
+		{1, 1, 0},
+		{10, 100, 900},
+	}
+
+	for _, tt := range tests {
+		if got := (PageInput{Page: tt.page, PerPage: tt.perPage}).Offset(); got != tt.want {
+			t.Errorf("Offset(page %d, per_page %d) = %d, want %d", tt.page, tt.perPage, got, tt.want)
+		}
+	}
+}
+
+func TestNewPagination(t *testing.T) {
+	requestURL, _ := url.Parse("/api/v1/players?q=rizky&page=2&per_page=10")
+
+	tests := []struct {
+		name       string
+		page       int
+		total      int64
+		totalPages int
+		next       string
+		prev       string
+	}{
+		{"empty", 1, 0, 0, "", ""},
+		{"single page", 1, 10, 1, "", ""},
+		{"exactly two pages", 1, 20, 2, "/api/v1/players?page=2&per_page=10&q=rizky", ""},
+		{"partial last page", 2, 21, 3, "/api/v1/players?page=3&per_page=10&q=rizky", "/api/v1/players?page=1&per_page=10&q=rizky"},
+		{"last page", 3, 21, 3, "", "/api/v1/players?page=2&per_page=10&q=rizky"},
+		{"past the end", 9, 21, 3, "", "/api/v1/players?page=3&per_page=10&q=rizky"},
+		{"past the end without data", 4, 0, 0, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPagination(PageInput{Page: tt.page, PerPage: 10}, tt.total, requestURL)
+
+			if p.TotalPages != tt.totalPages {
+				t.Errorf("TotalPages = %d, want %d", p.TotalPages, tt.totalPages)
+			}
+			if got := deref(p.Next); got != tt.next {
+				t.Errorf("Next = %q, want %q", got, tt.next)
+			}
+			if got := deref(p.Prev); got != tt.prev {
+				t.Errorf("Prev = %q, want %q", got, tt.prev)
+			}
+		})
+	}
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
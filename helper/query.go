@@ -0,0 +1,11 @@
+package helper
+
+import "strings"
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// LikePattern membuat pola "%term%" untuk pencarian LIKE dengan karakter
+// wildcard dari input user di-escape.
+func LikePattern(term string) string {
+	return "%" + likeEscaper.Replace(strings.TrimSpace(term)) + "%"
+}
@@ -0,0 +1,34 @@
+package helper
+
+import "errors"
+
+var ErrInUse = errors.New("resource is still referenced by other data")
+
+// InUseError dikembalikan saat data tidak bisa dihapus karena masih dipakai.
+// Blockers berisi ID data yang menghalangi, dikelompokkan per jenis
+// (misalnya "players", "matches").
+type InUseError struct {
+	Blockers map[string][]int
+}
+
+func (e *InUseError) Error() string {
+	return ErrInUse.Error()
+}
+
+func (e *InUseError) Is(target error) bool {
+	return target == ErrInUse
+}
+
+// NewInUseError mengembalikan nil jika tidak ada blocker sama sekali.
+func NewInUseError(blockers map[string][]int) error {
+	for kind, ids := range blockers {
+		if len(ids) == 0 {
+			delete(blockers, kind)
+		}
+	}
+	if len(blockers) == 0 {
+		return nil
+	}
+
+	return &InUseError{Blockers: blockers}
+}
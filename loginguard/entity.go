@@ -0,0 +1,32 @@
+package loginguard
+
+import "time"
+
+const (
+	ScopeAccount = "account"
+	ScopeIP      = "ip"
+)
+
+// Counter menyimpan jumlah login gagal berturut-turut per email atau per IP.
+type Counter struct {
+	ID            int    `gorm:"primaryKey;autoIncrement"`
+	Scope         string `gorm:"size:20;not null;uniqueIndex:idx_login_counter_subject"`
+	Subject       string `gorm:"size:191;not null;uniqueIndex:idx_login_counter_subject"`
+	Failures      int    `gorm:"not null;default:0"`
+	LockedUntil   *time.Time
+	LastFailureAt time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// Lockout adalah catatan setiap kali akun atau IP dikunci.
+type Lockout struct {
+	ID          int    `gorm:"primaryKey;autoIncrement"`
+	Scope       string `gorm:"size:20;not null;index"`
+	Subject     string `gorm:"size:191;not null;index"`
+	Failures    int    `gorm:"not null"`
+	LockedUntil time.Time
+	ClearedAt   *time.Time
+	ClearedByID *int
+	CreatedAt   time.Time
+}
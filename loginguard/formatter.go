@@ -0,0 +1,37 @@
+package loginguard
+
+import "time"
+
+type LockoutFormatter struct {
+	ID          int        `json:"id"`
+	Scope       string     `json:"scope"`
+	Subject     string     `json:"subject"`
+	Failures    int        `json:"failures"`
+	LockedUntil time.Time  `json:"locked_until"`
+	Active      bool       `json:"active"`
+	ClearedAt   *time.Time `json:"cleared_at"`
+	ClearedByID *int       `json:"cleared_by_id"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func FormatLockout(lockout Lockout) LockoutFormatter {
+	return LockoutFormatter{
+		ID:          lockout.ID,
+		Scope:       lockout.Scope,
+		Subject:     lockout.Subject,
+		Failures:    lockout.Failures,
+		LockedUntil: lockout.LockedUntil,
+		Active:      lockout.ClearedAt == nil && lockout.LockedUntil.After(time.Now()),
+		ClearedAt:   lockout.ClearedAt,
+		ClearedByID: lockout.ClearedByID,
+		CreatedAt:   lockout.CreatedAt,
+	}
+}
+
+func FormatLockouts(lockouts []Lockout) []LockoutFormatter {
+	formatted := []LockoutFormatter{}
+	for _, l := range lockouts {
+		formatted = append(formatted, FormatLockout(l))
+	}
+	return formatted
+}
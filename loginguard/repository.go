@@ -0,0 +1,73 @@
+package loginguard
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	FindCounter(scope, subject string) (Counter, error)
+	SaveCounter(counter Counter) (Counter, error)
+	ResetCounter(scope, subject string) error
+	CreateLockout(lockout Lockout) (Lockout, error)
+	FindLockouts(activeOnly bool) ([]Lockout, error)
+	FindLockoutByID(id int) (Lockout, error)
+	UpdateLockout(lockout Lockout) (Lockout, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *repository {
+	return &repository{db}
+}
+
+// FindCounter mengembalikan counter kosong (ID 0) jika belum ada
+func (r *repository) FindCounter(scope, subject string) (Counter, error) {
+	var counter Counter
+	err := r.db.Where("scope = ? AND subject = ?", scope, subject).First(&counter).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return Counter{Scope: scope, Subject: subject}, nil
+	}
+	return counter, err
+}
+
+func (r *repository) SaveCounter(counter Counter) (Counter, error) {
+	err := r.db.Save(&counter).Error
+	return counter, err
+}
+
+func (r *repository) ResetCounter(scope, subject string) error {
+	return r.db.Model(&Counter{}).
+		Where("scope = ? AND subject = ?", scope, subject).
+		Updates(map[string]interface{}{"failures": 0, "locked_until": nil}).Error
+}
+
+func (r *repository) CreateLockout(lockout Lockout) (Lockout, error) {
+	err := r.db.Create(&lockout).Error
+	return lockout, err
+}
+
+func (r *repository) FindLockouts(activeOnly bool) ([]Lockout, error) {
+	var lockouts []Lockout
+	query := r.db.Order("created_at DESC")
+	if activeOnly {
+		query = query.Where("cleared_at IS NULL AND locked_until > ?", time.Now())
+	}
+	err := query.Find(&lockouts).Error
+	return lockouts, err
+}
+
+func (r *repository) FindLockoutByID(id int) (Lockout, error) {
+	var lockout Lockout
+	err := r.db.First(&lockout, id).Error
+	return lockout, err
+}
+
+func (r *repository) UpdateLockout(lockout Lockout) (Lockout, error) {
+	err := r.db.Save(&lockout).Error
+	return lockout, err
+}
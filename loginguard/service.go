@@ -0,0 +1,161 @@
+package loginguard
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var ErrLocked = errors.New("too many failed login attempts, please try again later")
+
+type Service interface {
+	Check(email, ip string) (time.Duration, error)
+	RegisterFailure(email, ip string) error
+	RegisterSuccess(email string) error
+	GetLockouts(activeOnly bool) ([]Lockout, error)
+	ClearLockout(id int, clearedByID int) (Lockout, error)
+}
+
+type Config struct {
+	AccountThreshold int           // gagal berturut-turut sebelum akun dikunci
+	IPThreshold      int           // gagal berturut-turut sebelum IP dikunci
+	BaseLockout      time.Duration // durasi kunci pertama, berlipat dua tiap gagal berikutnya
+	MaxLockout       time.Duration
+	FailureWindow    time.Duration // counter direset jika tidak ada kegagalan selama ini
+}
+
+var DefaultConfig = Config{
+	AccountThreshold: 5,
+	IPThreshold:      20,
+	BaseLockout:      time.Minute,
+	MaxLockout:       time.Hour,
+	FailureWindow:    15 * time.Minute,
+}
+
+type service struct {
+	repository Repository
+	config     Config
+}
+
+func NewService(repository Repository, config Config) *service {
+	return &service{repository, config}
+}
+
+// Check mengembalikan sisa waktu kunci jika akun atau IP sedang dikunci.
+func (s *service) Check(email, ip string) (time.Duration, error) {
+	now := time.Now()
+	var retryAfter time.Duration
+
+	for _, key := range s.keys(email, ip) {
+		counter, err := s.repository.FindCounter(key.scope, key.subject)
+		if err != nil {
+			return 0, err
+		}
+		if counter.LockedUntil != nil && counter.LockedUntil.After(now) {
+			if wait := counter.LockedUntil.Sub(now); wait > retryAfter {
+				retryAfter = wait
+			}
+		}
+	}
+
+	if retryAfter > 0 {
+		return retryAfter, ErrLocked
+	}
+	return 0, nil
+}
+
+func (s *service) RegisterFailure(email, ip string) error {
+	now := time.Now()
+
+	for _, key := range s.keys(email, ip) {
+		counter, err := s.repository.FindCounter(key.scope, key.subject)
+		if err != nil {
+			return err
+		}
+
+		if now.Sub(counter.LastFailureAt) > s.config.FailureWindow {
+			counter.Failures = 0
+		}
+		counter.Failures++
+		counter.LastFailureAt = now
+
+		if counter.Failures >= key.threshold {
+			lockedUntil := now.Add(s.lockoutDuration(counter.Failures - key.threshold))
+			counter.LockedUntil = &lockedUntil
+
+			_, err := s.repository.CreateLockout(Lockout{
+				Scope:       key.scope,
+				Subject:     key.subject,
+				Failures:    counter.Failures,
+				LockedUntil: lockedUntil,
+				CreatedAt:   now,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if _, err := s.repository.SaveCounter(counter); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RegisterSuccess hanya mereset counter akun. Counter IP tetap berjalan agar
+// satu login berhasil tidak membuka kembali serangan dari IP yang sama.
+func (s *service) RegisterSuccess(email string) error {
+	return s.repository.ResetCounter(ScopeAccount, normalizeEmail(email))
+}
+
+func (s *service) GetLockouts(activeOnly bool) ([]Lockout, error) {
+	return s.repository.FindLockouts(activeOnly)
+}
+
+func (s *service) ClearLockout(id int, clearedByID int) (Lockout, error) {
+	lockout, err := s.repository.FindLockoutByID(id)
+	if err != nil {
+		return lockout, err
+	}
+
+	if err := s.repository.ResetCounter(lockout.Scope, lockout.Subject); err != nil {
+		return lockout, err
+	}
+
+	if lockout.ClearedAt == nil {
+		now := time.Now()
+		lockout.ClearedAt = &now
+		lockout.ClearedByID = &clearedByID
+	}
+
+	return s.repository.UpdateLockout(lockout)
+}
+
+func (s *service) lockoutDuration(extraFailures int) time.Duration {
+	duration := s.config.BaseLockout
+	for i := 0; i < extraFailures && duration < s.config.MaxLockout; i++ {
+		duration *= 2
+	}
+	if duration > s.config.MaxLockout {
+		duration = s.config.MaxLockout
+	}
+	return duration
+}
+
+type guardKey struct {
+	scope     string
+	subject   string
+	threshold int
+}
+
+func (s *service) keys(email, ip string) []guardKey {
+	return []guardKey{
+		{ScopeAccount, normalizeEmail(email), s.config.AccountThreshold},
+		{ScopeIP, ip, s.config.IPThreshold},
+	}
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,86 @@
+package mailer
+
+import (
+	"fmt"
+	"log"
+	"net/smtp"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(message Message) error
+}
+
+type smtpMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+func NewSMTPMailer(host string, port string, username string, password string, from string) *smtpMailer {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &smtpMailer{
+		addr: host + ":" + port,
+		auth: auth,
+		from: from,
+	}
+}
+
+func (m *smtpMailer) Send(message Message) error {
+	headers := []string{
+		"From: " + m.from,
+		"To: " + message.To,
+		"Subject: " + message.Subject,
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+	}
+	body := strings.Join(headers, "\r\n") + "\r\n\r\n" + message.Body
+
+	return smtp.SendMail(m.addr, m.auth, m.from, []string{message.To}, []byte(body))
+}
+
+// logMailer menulis email ke log dan (opsional) ke file, untuk development
+// dan pengujian tanpa server SMTP.
+type logMailer struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewLogMailer(path string) *logMailer {
+	return &logMailer{path: path}
+}
+
+func (m *logMailer) Send(message Message) error {
+	entry := fmt.Sprintf("[%s] To: %s\nSubject: %s\n\n%s\n-----\n",
+		time.Now().Format(time.RFC3339), message.To, message.Subject, message.Body)
+
+	if m.path == "" {
+		log.Print("📧 ", entry)
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	file, err := os.OpenFile(m.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.WriteString(entry)
+	return err
+}
@@ -1,28 +1,40 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
 	"github.com/golang-jwt/jwt"
 	"github.com/joho/godotenv"
-	"golang.org/x/crypto/bcrypt"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 
+	"footballteam/apikey"
+	"footballteam/audit"
 	"footballteam/auth"
 	"footballteam/handler"
 	"footballteam/helper"
+	"footballteam/loginguard"
+	"footballteam/mailer"
 	"footballteam/match"
 	"footballteam/match_result"
+	"footballteam/media"
+	"footballteam/oidc"
 	"footballteam/player"
+	"footballteam/staff"
+	"footballteam/storage"
 	"footballteam/team"
 	"footballteam/user"
+	"footballteam/venue"
 )
 
 func main() {
@@ -49,7 +61,10 @@ func main() {
 		dbUser, dbPassword, dbHost, dbPort, dbName,
 	)
 
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
+		// Duplicate key dari MySQL dikembalikan sebagai gorm.ErrDuplicatedKey
+		TranslateError: true,
+	})
 	if err != nil {
 		log.Fatal("❌ Failed to connect to database: ", err)
 	}
@@ -58,17 +73,53 @@ func main() {
 	// =========================
 	// Auto migrate tables
 	// =========================
+	// Data lama bisa berisi orphan yang membuat foreign key gagal dibuat
+	if err := checkOrphans(db, os.Getenv("CLEAN_ORPHANS") == "true"); err != nil {
+		log.Fatal("❌ Failed to migrate:", err)
+	}
+	if err := backfillTeamSlugs(db); err != nil {
+		log.Fatal("❌ Failed to migrate:", err)
+	}
+	if err := addUserRoleColumn(db); err != nil {
+		log.Fatal("❌ Failed to migrate:", err)
+	}
+
 	err = db.AutoMigrate(
 		&user.User{},
+		&venue.Venue{},
 		&team.Team{},
+		&team.TeamVersion{},
 		&player.Player{},
+		&player.Transfer{},
+		&staff.Staff{},
 		&match.Match{},
 		&match_result.MatchResult{},
 		&match_result.Goal{},
+		&auth.RefreshToken{},
+		&user.PasswordResetToken{},
+		&loginguard.Counter{},
+		&loginguard.Lockout{},
+		&auth.RevokedToken{},
+		&auth.UserRevocation{},
+		&apikey.APIKey{},
+		&user.RecoveryCode{},
+		&audit.Entry{},
 	)
 	if err != nil {
 		log.Fatal("❌ Failed to migrate:", err)
 	}
+	if err := backfillUserRoles(db); err != nil {
+		log.Fatal("❌ Failed to migrate:", err)
+	}
+	if err := migrateLogoKeys(db); err != nil {
+		log.Fatal("❌ Failed to migrate:", err)
+	}
+	if err := backfillTeamVersions(db); err != nil {
+		log.Fatal("❌ Failed to migrate:", err)
+	}
+	if err := migratePlayerPositions(db); err != nil {
+		log.Fatal("❌ Failed to migrate:", err)
+	}
 	fmt.Println("✅ Database migration completed")
 
 	// =========================
@@ -83,90 +134,278 @@ func main() {
 	// =========================
 	// Services & Handlers
 	// =========================
-	authService := auth.NewService()
+	keyring, err := loadKeyring()
+	if err != nil {
+		log.Fatal("❌ Failed to load JWT signing keys: ", err)
+	}
+
+	authRepository := auth.NewRepository(db)
+	authService := auth.NewService(authRepository, auth.Config{
+		Keyring:           keyring,
+		AccessTokenTTL:    durationFromEnv("ACCESS_TOKEN_TTL", auth.DefaultAccessTokenTTL),
+		RefreshTokenTTL:   durationFromEnv("REFRESH_TOKEN_TTL", auth.DefaultRefreshTokenTTL),
+		RevocationRefresh: durationFromEnv("TOKEN_REVOCATION_REFRESH", auth.DefaultRevocationRefresh),
+	})
 
 	userRepository := user.NewRepository(db)
-	userService := user.NewService(userRepository)
-	userHandler := handler.NewUserHandler(userService, authService)
+	userService := user.NewService(userRepository, newMailer(), user.Config{
+		PasswordResetURL: os.Getenv("PASSWORD_RESET_URL"),
+		PasswordResetTTL: durationFromEnv("PASSWORD_RESET_TTL", user.DefaultPasswordResetTTL),
+		TOTPIssuer:       os.Getenv("TOTP_ISSUER"),
+		OIDCDefaultRole:  os.Getenv("OIDC_DEFAULT_ROLE"),
+		TokenRevoker:     authService,
+	})
+	loginGuardRepository := loginguard.NewRepository(db)
+	loginGuardService := loginguard.NewService(loginGuardRepository, loginguard.Config{
+		AccountThreshold: intFromEnv("LOGIN_ACCOUNT_THRESHOLD", loginguard.DefaultConfig.AccountThreshold),
+		IPThreshold:      intFromEnv("LOGIN_IP_THRESHOLD", loginguard.DefaultConfig.IPThreshold),
+		BaseLockout:      durationFromEnv("LOGIN_BASE_LOCKOUT", loginguard.DefaultConfig.BaseLockout),
+		MaxLockout:       durationFromEnv("LOGIN_MAX_LOCKOUT", loginguard.DefaultConfig.MaxLockout),
+		FailureWindow:    durationFromEnv("LOGIN_FAILURE_WINDOW", loginguard.DefaultConfig.FailureWindow),
+	})
+	loginGuardHandler := handler.NewLoginGuardHandler(loginGuardService)
+
+	userHandler := handler.NewUserHandler(userService, authService, loginGuardService)
+
+	apiKeyRepository := apikey.NewRepository(db)
+	apiKeyService := apikey.NewService(apiKeyRepository)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService)
+	authHandler := handler.NewAuthHandler(authService)
+
+	auditRepository := audit.NewRepository(db)
+	auditService := audit.NewService(auditRepository)
+	auditHandler := handler.NewAuditHandler(auditService)
+
+	venueRepository := venue.NewRepository(db)
+	venueService := venue.NewService(venueRepository, auditService)
+	venueHandler := handler.NewVenueHandler(venueService)
 
 	teamRepository := team.NewRepository(db)
-	teamService := team.NewService(teamRepository)
-	teamHandler := handler.NewTeamHandler(teamService)
+	mediaStorage, localStorageDir := newStorage()
+	maxLogoSize := int64(intFromEnv("LOGO_MAX_SIZE", media.DefaultMaxSize))
+	mediaService := media.NewService(mediaStorage, media.Config{
+		MaxSize: maxLogoSize,
+	})
+	teamService := team.NewService(teamRepository, auditService, mediaService)
 
 	playerRepository := player.NewRepository(db)
-	playerService := player.NewService(playerRepository)
+	transferWindows, err := player.ParseTransferWindows(os.Getenv("TRANSFER_WINDOWS"))
+	if err != nil {
+		log.Fatal("❌ Invalid TRANSFER_WINDOWS: ", err)
+	}
+	playerService := player.NewService(playerRepository, auditService, player.Config{
+		TransferWindows: transferWindows,
+	})
 	playerHandler := handler.NewPlayerHandler(playerService)
 
+	staffRepository := staff.NewRepository(db)
+	staffService := staff.NewService(staffRepository, auditService)
+	staffHandler := handler.NewStaffHandler(staffService)
+
 	matchRepository := match.NewRepository(db)
-	matchService := match.NewService(matchRepository)
-	matchHandler := handler.NewMatchHandler(matchService)
+	matchService := match.NewService(matchRepository, auditService, match.Config{
+		VenueClashWindow: durationFromEnv("MATCH_VENUE_CLASH_WINDOW", match.DefaultVenueClashWindow),
+	})
+	matchHandler := handler.NewMatchHandler(matchService, mediaStorage)
 
 	matchResultRepository := match_result.NewRepository(db)
-	matchResultService := match_result.NewService(matchResultRepository, playerService, matchService)
-	matchResultHandler := handler.NewMatchResultHandler(matchResultService, playerService)
+	matchResultService := match_result.NewService(matchResultRepository, playerService, matchService, auditService)
+	matchResultHandler := handler.NewMatchResultHandler(matchResultService, playerService, mediaStorage)
+
+	teamHandler := handler.NewTeamHandler(teamService, playerService, staffService, matchService, matchResultService, mediaStorage, maxLogoSize)
 
 	// =========================
 	// Router
 	// =========================
 	router := gin.Default()
+
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		if err := player.RegisterValidations(v); err != nil {
+			log.Fatal("❌ Failed to register validations: ", err)
+		}
+	}
+
+	// Rate limit login memakai IP klien, jadi X-Forwarded-For hanya dipercaya
+	// dari proxy yang terdaftar
+	var trustedProxies []string
+	if value := os.Getenv("TRUSTED_PROXIES"); value != "" {
+		trustedProxies = strings.Split(value, ",")
+	}
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		log.Fatal("❌ Invalid TRUSTED_PROXIES: ", err)
+	}
+
+	router.GET("/.well-known/jwks.json", authHandler.JWKS)
+
 	api := router.Group("/api/v1")
 
 	// Public routes
 	api.POST("/sessions", userHandler.Login)
+	api.POST("/sessions/refresh", userHandler.RefreshSession)
+	api.POST("/sessions/2fa", userHandler.VerifyTwoFactor)
+	api.POST("/sessions/2fa/enroll", userHandler.BeginLoginTwoFactorEnrollment)
+	api.POST("/sessions/2fa/confirm", userHandler.ConfirmLoginTwoFactorEnrollment)
+	api.POST("/password/forgot", userHandler.ForgotPassword)
+	api.POST("/password/reset", userHandler.ResetPassword)
+
+	// Login lewat identity provider organisasi (opsional)
+	if issuerURL := os.Getenv("OIDC_ISSUER_URL"); issuerURL != "" {
+		if role := os.Getenv("OIDC_DEFAULT_ROLE"); role != "" && (!user.IsValidRole(role) || role == user.RoleManager) {
+			log.Fatal("❌ Invalid OIDC_DEFAULT_ROLE: ", role)
+		}
+
+		oidcService := oidc.NewService(oidc.Config{
+			IssuerURL:    issuerURL,
+			ClientID:     os.Getenv("OIDC_CLIENT_ID"),
+			ClientSecret: os.Getenv("OIDC_CLIENT_SECRET"),
+			RedirectURL:  os.Getenv("OIDC_REDIRECT_URL"),
+			Scopes:       strings.Fields(os.Getenv("OIDC_SCOPES")),
+			StateTTL:     durationFromEnv("OIDC_STATE_TTL", oidc.DefaultStateTTL),
+		})
+		oidcHandler := handler.NewOIDCHandler(oidcService, userService, authService)
+
+		api.GET("/sessions/oidc/login", oidcHandler.Login)
+		api.GET("/sessions/oidc/callback", oidcHandler.Callback)
+	}
 
 	// Teams
 	api.GET("/teams", teamHandler.GetTeams)
 	api.GET("/teams/:id", teamHandler.GetTeamByID)
+	api.GET("/teams/:id/history", teamHandler.GetTeamHistory)
 
 	// Players
 	api.GET("/players", playerHandler.GetPlayers)
 	api.GET("/players/:id", playerHandler.GetPlayerByID)
 	api.GET("/players/team/:team_id", playerHandler.GetPlayersByTeam)
+	api.GET("/players/:id/transfers", playerHandler.GetTransfers)
+
+	// Staff
+	api.GET("/staff", staffHandler.GetStaff)
+	api.GET("/staff/:id", staffHandler.GetStaffByID)
+	api.GET("/staff/team/:team_id", staffHandler.GetStaffByTeam)
 
 	// Matches
 	api.GET("/matches", matchHandler.GetMatches)
 	api.GET("/matches/:id", matchHandler.GetMatchByID)
 
+	// Venues
+	api.GET("/venues", venueHandler.GetVenues)
+	api.GET("/venues/:id", venueHandler.GetVenueByID)
+
 	// MatchResults
 	api.GET("/match_results", matchResultHandler.GetMatchResults)
 	api.GET("/match_results/:id", matchResultHandler.GetMatchResultByID)
 	api.GET("/match_results/report", matchResultHandler.GetMatchResultsReport)
 
-	// Protected routes (admin only)
+	// Protected routes (butuh login, hak akses dicek per route)
 	protected := api.Group("/")
-	protected.Use(authMiddleware(authService, userService))
-
-	// Teams (admin)
-	protected.POST("/teams", teamHandler.CreateTeam)
-	protected.PUT("/teams/:id", teamHandler.UpdateTeam)
-	protected.DELETE("/teams/:id", teamHandler.DeleteTeam)
-	protected.POST("/teams/:id/logo", teamHandler.UploadLogo)
-
-	// Players (admin)
-	protected.POST("/players", playerHandler.CreatePlayer)
-	protected.PUT("/players/:id", playerHandler.UpdatePlayer)
-	protected.DELETE("/players/:id", playerHandler.DeletePlayer)
-
-	// Matches (admin)
-	protected.POST("/matches", matchHandler.CreateMatch)
-	protected.PUT("/matches/:id", matchHandler.UpdateMatch)
-	protected.DELETE("/matches/:id", matchHandler.DeleteMatch)
-
-	// MatchResults (admin)
-	protected.POST("/match_results", matchResultHandler.CreateMatchResult)
-
-	// Static uploads
-	api.Static("/uploads", "./uploads")
+	protected.Use(authMiddleware(authService, userService, apiKeyService))
+
+	// Sessions
+	protected.DELETE("/sessions", requireUserSession(), userHandler.Logout)
+	protected.DELETE("/sessions/all", requireUserSession(), userHandler.LogoutEverywhere)
+
+	// Two-factor authentication (akun sendiri)
+	protected.POST("/me/2fa", requireUserSession(), userHandler.BeginTwoFactorEnrollment)
+	protected.POST("/me/2fa/confirm", requireUserSession(), userHandler.ConfirmTwoFactorEnrollment)
+	protected.DELETE("/me/2fa", requireUserSession(), userHandler.DisableTwoFactor)
+
+	// Teams (write)
+	protected.POST("/teams", requirePermission(user.PermissionWriteTeams), teamHandler.CreateTeam)
+	protected.PUT("/teams/:id", requirePermission(user.PermissionWriteTeams), teamHandler.UpdateTeam)
+	protected.DELETE("/teams/:id", requirePermission(user.PermissionDeleteTeams), teamHandler.DeleteTeam)
+	protected.POST("/teams/:id/logo", requirePermission(user.PermissionUploadTeamLogo), teamHandler.UploadLogo)
+	protected.POST("/teams/:id/restore", requirePermission(user.PermissionDeleteTeams), teamHandler.RestoreTeam)
+
+	// Players (write)
+	protected.POST("/players", requirePermission(user.PermissionWritePlayers), playerHandler.CreatePlayer)
+	protected.PUT("/players/:id", requirePermission(user.PermissionWritePlayers), playerHandler.UpdatePlayer)
+	protected.DELETE("/players/:id", requirePermission(user.PermissionDeletePlayers), playerHandler.DeletePlayer)
+	protected.POST("/players/:id/restore", requirePermission(user.PermissionDeletePlayers), playerHandler.RestorePlayer)
+	protected.POST("/players/:id/transfers", requirePermission(user.PermissionWritePlayers), playerHandler.TransferPlayer)
+
+	// Staff (write)
+	protected.POST("/staff", requirePermission(user.PermissionWriteStaff), staffHandler.CreateStaff)
+	protected.PUT("/staff/:id", requirePermission(user.PermissionWriteStaff), staffHandler.UpdateStaff)
+	protected.DELETE("/staff/:id", requirePermission(user.PermissionDeleteStaff), staffHandler.DeleteStaff)
+	protected.POST("/staff/:id/restore", requirePermission(user.PermissionDeleteStaff), staffHandler.RestoreStaff)
+
+	// Matches (write)
+	protected.POST("/matches", requirePermission(user.PermissionWriteMatches), matchHandler.CreateMatch)
+	protected.PUT("/matches/:id", requirePermission(user.PermissionWriteMatches), matchHandler.UpdateMatch)
+	protected.DELETE("/matches/:id", requirePermission(user.PermissionDeleteMatches), matchHandler.DeleteMatch)
+	protected.POST("/matches/:id/restore", requirePermission(user.PermissionDeleteMatches), matchHandler.RestoreMatch)
+
+	// Venues (write)
+	protected.POST("/venues", requirePermission(user.PermissionWriteVenues), venueHandler.CreateVenue)
+	protected.PUT("/venues/:id", requirePermission(user.PermissionWriteVenues), venueHandler.UpdateVenue)
+	protected.DELETE("/venues/:id", requirePermission(user.PermissionDeleteVenues), venueHandler.DeleteVenue)
+
+	// MatchResults (write)
+	protected.POST("/match_results", requirePermission(user.PermissionWriteMatchResults), matchResultHandler.CreateMatchResult)
+	protected.DELETE("/match_results/:id", requirePermission(user.PermissionDeleteMatchResults), matchResultHandler.DeleteMatchResult)
+	protected.POST("/match_results/:id/restore", requirePermission(user.PermissionDeleteMatchResults), matchResultHandler.RestoreMatchResult)
+
+	// Trash: daftar data yang di-soft delete dan hapus permanen (purge)
+	protected.GET("/trash/teams", requirePermission(user.PermissionDeleteTeams), teamHandler.GetTrashedTeams)
+	protected.GET("/trash/players", requirePermission(user.PermissionDeletePlayers), playerHandler.GetTrashedPlayers)
+	protected.GET("/trash/staff", requirePermission(user.PermissionDeleteStaff), staffHandler.GetTrashedStaff)
+	protected.GET("/trash/matches", requirePermission(user.PermissionDeleteMatches), matchHandler.GetTrashedMatches)
+	protected.GET("/trash/match_results", requirePermission(user.PermissionDeleteMatchResults), matchResultHandler.GetTrashedMatchResults)
+	protected.DELETE("/trash/teams/:id", requirePermission(user.PermissionPurgeTrash), teamHandler.PurgeTeam)
+	protected.DELETE("/trash/players/:id", requirePermission(user.PermissionPurgeTrash), playerHandler.PurgePlayer)
+	protected.DELETE("/trash/staff/:id", requirePermission(user.PermissionPurgeTrash), staffHandler.PurgeStaff)
+	protected.DELETE("/trash/matches/:id", requirePermission(user.PermissionPurgeTrash), matchHandler.PurgeMatch)
+	protected.DELETE("/trash/match_results/:id", requirePermission(user.PermissionPurgeTrash), matchResultHandler.PurgeMatchResult)
+
+	// Users (admin)
+	protected.GET("/users", requirePermission(user.PermissionManageUsers), userHandler.GetUsers)
+	protected.POST("/users", requirePermission(user.PermissionManageUsers), userHandler.CreateUser)
+	protected.PUT("/users/:id", requirePermission(user.PermissionManageUsers), userHandler.UpdateUser)
+	protected.DELETE("/users/:id", requirePermission(user.PermissionManageUsers), userHandler.DeactivateUser)
+	protected.POST("/users/:id/activate", requirePermission(user.PermissionManageUsers), userHandler.ActivateUser)
+	protected.DELETE("/users/:id/2fa", requirePermission(user.PermissionManageUsers), userHandler.ResetTwoFactor)
+
+	// API keys (admin)
+	protected.GET("/api_keys", requirePermission(user.PermissionManageAPIKeys), apiKeyHandler.GetAPIKeys)
+	protected.POST("/api_keys", requirePermission(user.PermissionManageAPIKeys), apiKeyHandler.CreateAPIKey)
+	protected.DELETE("/api_keys/:id", requirePermission(user.PermissionManageAPIKeys), apiKeyHandler.RevokeAPIKey)
+
+	// Login lockouts (admin)
+	protected.GET("/lockouts", requirePermission(user.PermissionManageUsers), loginGuardHandler.GetLockouts)
+	protected.DELETE("/lockouts/:id", requirePermission(user.PermissionManageUsers), loginGuardHandler.ClearLockout)
+
+	// Audit log (admin)
+	protected.GET("/audit", requirePermission(user.PermissionReadAudit), auditHandler.GetEntries)
+
+	// Static uploads, hanya jika file disimpan di disk lokal
+	if localStorageDir != "" {
+		api.Static("/uploads", localStorageDir)
+	}
 
 	// Start server
 	router.Run(":" + port)
 }
 
 // =========================
-// Middleware: Auth JWT
+// Middleware: Auth JWT / API key
 // =========================
-func authMiddleware(authService auth.Service, userService user.Service) gin.HandlerFunc {
+func authMiddleware(authService auth.Service, userService user.Service, apiKeyService apikey.Service) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// Integrasi mesin-ke-mesin memakai header X-API-Key, bukan JWT
+		if plainKey := c.GetHeader("X-API-Key"); plainKey != "" {
+			key, err := apiKeyService.Authenticate(plainKey)
+			if err != nil {
+				response := helper.APIResponse("Unauthorized", http.StatusUnauthorized, "error", gin.H{"error_code": "invalid_api_key"})
+				c.AbortWithStatusJSON(http.StatusUnauthorized, response)
+				return
+			}
+
+			c.Set("currentAPIKey", key)
+			c.Next()
+			return
+		}
+
 		authHeader := c.GetHeader("Authorization")
 		if !strings.Contains(authHeader, "Bearer") {
 			response := helper.APIResponse("Unauthorized", http.StatusUnauthorized, "error", nil)
@@ -181,6 +420,16 @@ func authMiddleware(authService auth.Service, userService user.Service) gin.Hand
 		}
 
 		token, err := authService.ValidateToken(tokenString)
+		if errors.Is(err, auth.ErrTokenExpired) {
+			response := helper.APIResponse("Token expired", http.StatusUnauthorized, "error", gin.H{"error_code": "token_expired"})
+			c.AbortWithStatusJSON(http.StatusUnauthorized, response)
+			return
+		}
+		if errors.Is(err, auth.ErrTokenRevoked) {
+			response := helper.APIResponse("Token revoked", http.StatusUnauthorized, "error", gin.H{"error_code": "token_revoked"})
+			c.AbortWithStatusJSON(http.StatusUnauthorized, response)
+			return
+		}
 		if err != nil {
 			response := helper.APIResponse("Unauthorized", http.StatusUnauthorized, "error", nil)
 			c.AbortWithStatusJSON(http.StatusUnauthorized, response)
@@ -202,11 +451,184 @@ func authMiddleware(authService auth.Service, userService user.Service) gin.Hand
 			return
 		}
 
+		if !user.IsActive() {
+			response := helper.APIResponse("Account deactivated", http.StatusUnauthorized, "error", gin.H{"error_code": "account_deactivated"})
+			c.AbortWithStatusJSON(http.StatusUnauthorized, response)
+			return
+		}
+
 		c.Set("currentUser", user)
+		c.Set("currentToken", token)
+		c.Next()
+	}
+}
+
+// =========================
+// Middleware: Role permission
+// =========================
+func requirePermission(permission user.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		allowed := false
+		if value, ok := c.Get("currentAPIKey"); ok {
+			allowed = value.(apikey.APIKey).HasScope(permission)
+		} else {
+			allowed = c.MustGet("currentUser").(user.User).Can(permission)
+		}
+
+		if !allowed {
+			response := helper.APIResponse("Forbidden", http.StatusForbidden, "error", gin.H{"error_code": "forbidden"})
+			c.AbortWithStatusJSON(http.StatusForbidden, response)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// requireUserSession menolak API key untuk endpoint yang hanya masuk akal
+// untuk user yang login (misalnya logout)
+func requireUserSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := c.Get("currentUser"); !ok {
+			response := helper.APIResponse("Forbidden", http.StatusForbidden, "error", gin.H{"error_code": "user_session_required"})
+			c.AbortWithStatusJSON(http.StatusForbidden, response)
+			return
+		}
+
 		c.Next()
 	}
 }
 
+// loadKeyring membaca kunci JWT dari JWT_SECRET dan/atau JWT_KEYS_DIR.
+// JWT_SIGNING_KEY_ID menentukan kunci yang dipakai untuk menandatangani token
+// baru; kunci lain tetap diterima saat verifikasi sehingga rotasi kunci tidak
+// membuat semua user logout.
+func loadKeyring() (*auth.Keyring, error) {
+	var keys []auth.Key
+
+	if secret := os.Getenv("JWT_SECRET"); secret != "" {
+		secretID := os.Getenv("JWT_SECRET_KEY_ID")
+		if secretID == "" {
+			secretID = "default"
+		}
+
+		key, err := auth.NewHMACKey(secretID, []byte(secret))
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	if dir := os.Getenv("JWT_KEYS_DIR"); dir != "" {
+		dirKeys, err := auth.LoadKeysFromDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, dirKeys...)
+	}
+
+	if len(keys) == 0 {
+		// Hanya untuk development: token tidak berlaku lagi setelah restart
+		secret, err := helper.GenerateRandomToken(32)
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := auth.NewHMACKey("ephemeral", []byte(secret))
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+		log.Println("❌ Warning: JWT_SECRET / JWT_KEYS_DIR not set, using an ephemeral signing key")
+	}
+
+	activeID := os.Getenv("JWT_SIGNING_KEY_ID")
+	if activeID == "" {
+		if len(keys) > 1 {
+			return nil, errors.New("JWT_SIGNING_KEY_ID is required when more than one key is configured")
+		}
+		activeID = keys[0].ID
+	}
+
+	return auth.NewKeyring(activeID, keys...)
+}
+
+// newMailer memilih SMTP jika MAIL_DRIVER=smtp, selain itu email hanya ditulis
+// ke log (atau ke MAIL_LOG_FILE) untuk development.
+func newMailer() mailer.Mailer {
+	if os.Getenv("MAIL_DRIVER") == "smtp" {
+		smtpPort := os.Getenv("SMTP_PORT")
+		if smtpPort == "" {
+			smtpPort = "587"
+		}
+
+		return mailer.NewSMTPMailer(
+			os.Getenv("SMTP_HOST"),
+			smtpPort,
+			os.Getenv("SMTP_USERNAME"),
+			os.Getenv("SMTP_PASSWORD"),
+			os.Getenv("MAIL_FROM"),
+		)
+	}
+
+	return mailer.NewLogMailer(os.Getenv("MAIL_LOG_FILE"))
+}
+
+// newStorage memilih backend upload dari STORAGE_DRIVER. Direktori lokal
+// dikembalikan supaya bisa disajikan lewat route static; kosong untuk S3.
+func newStorage() (storage.Storage, string) {
+	switch driver := os.Getenv("STORAGE_DRIVER"); driver {
+	case "", "local":
+		local := storage.NewLocal(os.Getenv("STORAGE_LOCAL_DIR"), os.Getenv("STORAGE_PUBLIC_URL"))
+		return local, local.Dir()
+	case "s3":
+		if os.Getenv("S3_ENDPOINT") == "" || os.Getenv("S3_BUCKET") == "" {
+			log.Fatal("❌ S3_ENDPOINT and S3_BUCKET are required when STORAGE_DRIVER=s3")
+		}
+		return storage.NewS3(storage.S3Config{
+			Endpoint:        os.Getenv("S3_ENDPOINT"),
+			Region:          os.Getenv("S3_REGION"),
+			Bucket:          os.Getenv("S3_BUCKET"),
+			AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+			PublicURL:       os.Getenv("STORAGE_PUBLIC_URL"),
+		}), ""
+	default:
+		log.Fatal("❌ Unknown STORAGE_DRIVER: ", driver)
+		return nil, ""
+	}
+}
+
+func intFromEnv(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("❌ Invalid %s %q, using default %d", key, value, fallback)
+		return fallback
+	}
+
+	return number
+}
+
+// durationFromEnv membaca durasi seperti "15m" atau "720h" dari env
+func durationFromEnv(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("❌ Invalid %s %q, using default %s", key, value, fallback)
+		return fallback
+	}
+
+	return duration
+}
 
 // Seeder admin user default
 func seedAdminUser(db *gorm.DB) {
@@ -228,7 +650,7 @@ func seedAdminUser(db *gorm.DB) {
 
 	if count == 0 {
 		// Hash password
-		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(adminPassword), bcrypt.DefaultCost)
+		hashedPassword, err := helper.HashPassword(adminPassword)
 		if err != nil {
 			log.Fatal("Failed to hash password:", err)
 		}
@@ -236,7 +658,8 @@ func seedAdminUser(db *gorm.DB) {
 		admin := user.User{
 			Name:         adminName,
 			Email:        adminEmail,
-			PasswordHash: string(hashedPassword),
+			PasswordHash: hashedPassword,
+			Role:         user.RoleAdmin,
 			CreatedAt:    time.Now(),
 			UpdatedAt:    time.Now(),
 		}
@@ -261,24 +684,39 @@ func seedTeams(db *gorm.DB) {
 
 	teams := []team.Team{
 		{
-			Name:        "Garuda FC",
-			Logo:        "",
-			YearFounded: 1998,
-			Address:     "Jl. Merdeka No. 1",
-			City:        "Jakarta",
-			CreatedAt:   time.Now(),
-			UpdatedAt:   time.Now(),
+			Name:           "Garuda FC",
+			NameKey:        team.NameKey("Garuda FC"),
+			Slug:           team.Slugify("Garuda FC"),
+			ShortName:      "Garuda",
+			Abbreviation:   "GAR",
+			PrimaryColor:   "#D32F2F",
+			SecondaryColor: "#FFFFFF",
+			Logo:           "",
+			YearFounded:    1998,
+			Address:        "Jl. Merdeka No. 1",
+			City:           "Jakarta",
+			CreatedAt:      time.Now(),
+			UpdatedAt:      time.Now(),
 		},
 		{
-			Name:        "Pahlawan FC",
-			Logo:        "",
-			YearFounded:  2005,
-			Address:     "Jl. Pahlawan No. 7",
-			City:        "Surabaya",
-			CreatedAt:   time.Now(),
-			UpdatedAt:   time.Now(),
+			Name:           "Pahlawan FC",
+			NameKey:        team.NameKey("Pahlawan FC"),
+			Slug:           team.Slugify("Pahlawan FC"),
+			ShortName:      "Pahlawan",
+			Abbreviation:   "PHL",
+			PrimaryColor:   "#1565C0",
+			SecondaryColor: "#FFD600",
+			Logo:           "",
+			YearFounded:    2005,
+			Address:        "Jl. Pahlawan No. 7",
+			City:           "Surabaya",
+			CreatedAt:      time.Now(),
+			UpdatedAt:      time.Now(),
 		},
 	}
+	for i := range teams {
+		teams[i].Versions = []team.TeamVersion{teams[i].Snapshot(teams[i].CreatedAt)}
+	}
 
 	if err := db.Create(&teams).Error; err != nil {
 		log.Println("Failed to seed teams:", err)
@@ -312,7 +750,7 @@ func seedPlayers(db *gorm.DB) {
 			Name:         "Rizky Hadi",
 			Height:       178,
 			Weight:       72,
-			Position:     "Penyerang",
+			Position:     "ST",
 			Number:       9,
 			TeamID:       garudaFC.ID,
 			CreatedAt:    time.Now(),
@@ -322,7 +760,7 @@ func seedPlayers(db *gorm.DB) {
 			Name:         "Dimas Putra",
 			Height:       180,
 			Weight:       75,
-			Position:     "Gelandang",
+			Position:     "AM",
 			Number:       10,
 			TeamID:       garudaFC.ID,
 			CreatedAt:    time.Now(),
@@ -332,7 +770,7 @@ func seedPlayers(db *gorm.DB) {
 			Name:         "Andri Saputra",
 			Height:       185,
 			Weight:       80,
-			Position:     "Penjaga Gawang",
+			Position:     "GK",
 			Number:       1,
 			TeamID:       garudaFC.ID,
 			CreatedAt:    time.Now(),
@@ -344,7 +782,7 @@ func seedPlayers(db *gorm.DB) {
 			Name:         "Budi Santoso",
 			Height:       177,
 			Weight:       70,
-			Position:     "Bertahan",
+			Position:     "CB",
 			Number:       5,
 			TeamID:       pahlawanFC.ID,
 			CreatedAt:    time.Now(),
@@ -354,7 +792,7 @@ func seedPlayers(db *gorm.DB) {
 			Name:         "Yoga Prasetyo",
 			Height:       174,
 			Weight:       68,
-			Position:     "Gelandang",
+			Position:     "CM",
 			Number:       8,
 			TeamID:       pahlawanFC.ID,
 			CreatedAt:    time.Now(),
@@ -364,7 +802,7 @@ func seedPlayers(db *gorm.DB) {
 			Name:         "Ahmad Fadli",
 			Height:       182,
 			Weight:       78,
-			Position:     "Penyerang",
+			Position:     "LW",
 			Number:       11,
 			TeamID:       pahlawanFC.ID,
 			CreatedAt:    time.Now(),
@@ -381,28 +819,30 @@ func seedPlayers(db *gorm.DB) {
 }
 
 func seedMatch(db *gorm.DB) {
+	var garudaFC, pahlawanFC team.Team
+	db.Where("name = ?", "Garuda FC").First(&garudaFC)
+	db.Where("name = ?", "Pahlawan FC").First(&pahlawanFC)
+
+	// Jangan pakai ID hardcode, tim bisa saja punya ID lain
+	if garudaFC.ID == 0 || pahlawanFC.ID == 0 {
+		fmt.Println("❌ Teams not found, skipping match seeder.")
+		return
+	}
+
 	matches := []match.Match{
 		{
 			Date:       "2025-10-20",
 			Time:       "15:00",
-			HomeTeamID: 1,
-			AwayTeamID: 2,
-			CreatedAt:  time.Now(),
-			UpdatedAt:  time.Now(),
-		},
-		{
-			Date:       "2025-10-22",
-			Time:       "18:30",
-			HomeTeamID: 3,
-			AwayTeamID: 4,
+			HomeTeamID: garudaFC.ID,
+			AwayTeamID: pahlawanFC.ID,
 			CreatedAt:  time.Now(),
 			UpdatedAt:  time.Now(),
 		},
 		{
 			Date:       "2025-10-25",
 			Time:       "20:00",
-			HomeTeamID: 2,
-			AwayTeamID: 3,
+			HomeTeamID: pahlawanFC.ID,
+			AwayTeamID: garudaFC.ID,
 			CreatedAt:  time.Now(),
 			UpdatedAt:  time.Now(),
 		},
@@ -429,30 +869,49 @@ func seedMatchResults(db *gorm.DB) {
 		return
 	}
 
+	var garudaFC, pahlawanFC team.Team
+	db.Where("name = ?", "Garuda FC").First(&garudaFC)
+	db.Where("name = ?", "Pahlawan FC").First(&pahlawanFC)
+
+	var firstMatch match.Match
+	db.Where("date = ? AND home_team_id = ? AND away_team_id = ?", "2025-10-20", garudaFC.ID, pahlawanFC.ID).First(&firstMatch)
+
+	scorers := map[string]player.Player{}
+	for _, name := range []string{"Rizky Hadi", "Dimas Putra", "Budi Santoso"} {
+		var p player.Player
+		db.Where("name = ?", name).First(&p)
+		scorers[name] = p
+	}
+
+	if firstMatch.ID == 0 || scorers["Rizky Hadi"].ID == 0 || scorers["Dimas Putra"].ID == 0 || scorers["Budi Santoso"].ID == 0 {
+		fmt.Println("❌ Match or players not found, skipping match result seeder.")
+		return
+	}
+
 	results := []match_result.MatchResult{
 		{
-			MatchID:   1,
+			MatchID:   firstMatch.ID,
 			HomeScore: 2,
 			AwayScore: 1,
 			Status:    "Home Menang",
 			Goals: []match_result.Goal{
 				{
-					PlayerID: 1, // ID pemain yang mencetak gol
-					TeamID:   1, // Home team
+					PlayerID: scorers["Rizky Hadi"].ID, // pemain yang mencetak gol
+					TeamID:   garudaFC.ID,              // Home team
 					Minute:   15,
 					CreatedAt: time.Now(),
 					UpdatedAt: time.Now(),
 				},
 				{
-					PlayerID: 2,
-					TeamID:   1,
+					PlayerID: scorers["Dimas Putra"].ID,
+					TeamID:   garudaFC.ID,
 					Minute:   60,
 					CreatedAt: time.Now(),
 					UpdatedAt: time.Now(),
 				},
 				{
-					PlayerID: 4,
-					TeamID:   2, // Away team
+					PlayerID: scorers["Budi Santoso"].ID,
+					TeamID:   pahlawanFC.ID, // Away team
 					Minute:   75,
 					CreatedAt: time.Now(),
 					UpdatedAt: time.Now(),
@@ -2,20 +2,35 @@ package match
 
 import (
 	"footballteam/team"
+	"footballteam/venue"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type Match struct {
-	ID          int        `gorm:"primaryKey" json:"id"`
-	Date        string     `json:"date"`
-	Time        string     `json:"time"`
-	HomeTeamID  int        `json:"home_team_id"`
-	AwayTeamID  int        `json:"away_team_id"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
-	DeletedAt   *time.Time `gorm:"index" json:"deleted_at,omitempty"`
+	ID         int            `gorm:"primaryKey" json:"id"`
+	Date       string         `json:"date"`
+	Time       string         `json:"time"`
+	HomeTeamID int            `json:"home_team_id"`
+	AwayTeamID int            `json:"away_team_id"`
+	VenueID    *int           `gorm:"index" json:"venue_id"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
 
 	// Relasi
-	HomeTeam team.Team `gorm:"foreignKey:HomeTeamID"`
-	AwayTeam team.Team `gorm:"foreignKey:AwayTeamID"`
+	HomeTeam team.Team    `gorm:"foreignKey:HomeTeamID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT"`
+	AwayTeam team.Team    `gorm:"foreignKey:AwayTeamID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT"`
+	Venue    *venue.Venue `gorm:"constraint:OnUpdate:CASCADE,OnDelete:RESTRICT"`
+}
+
+// Kickoff menggabungkan Date dan Time dalam zona waktu lokal server. Jadwal
+// yang tidak valid menghasilkan zero time.
+func (m Match) Kickoff() time.Time {
+	kickoff, err := time.ParseInLocation(dateLayout+" "+timeLayout, m.Date+" "+m.Time, time.Local)
+	if err != nil {
+		return time.Time{}
+	}
+	return kickoff
 }
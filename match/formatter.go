@@ -1,39 +1,80 @@
 package match
 
+import (
+	"time"
+
+	"footballteam/media"
+	"footballteam/team"
+)
+
+// TeamFormatter memakai nama dan logo tim yang berlaku saat kick-off, bukan
+// yang sekarang.
 type TeamFormatter struct {
 	ID   int    `json:"id"`
 	Name string `json:"name"`
+	Logo string `json:"logo"`
+}
+
+type VenueFormatter struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
 }
 
 type MatchFormatter struct {
-	ID       int           `json:"id"`
-	Date     string        `json:"date"`
-	Time     string        `json:"time"`
-	HomeTeam TeamFormatter `json:"home_team"`
-	AwayTeam TeamFormatter `json:"away_team"`
-}
-
-func FormatMatch(m Match) MatchFormatter {
-	return MatchFormatter{
-		ID:   m.ID,
-		Date: m.Date,
-		Time: m.Time,
-		HomeTeam: TeamFormatter{
-			ID:   m.HomeTeam.ID,
-			Name: m.HomeTeam.Name,
-		},
-		AwayTeam: TeamFormatter{
-			ID:   m.AwayTeam.ID,
-			Name: m.AwayTeam.Name,
-		},
+	ID       int             `json:"id"`
+	Date     string          `json:"date"`
+	Time     string          `json:"time"`
+	HomeTeam TeamFormatter   `json:"home_team"`
+	AwayTeam TeamFormatter   `json:"away_team"`
+	Venue    *VenueFormatter `json:"venue"`
+}
+
+func FormatMatch(m Match, urls team.URLResolver) MatchFormatter {
+	kickoff := m.Kickoff()
+	formatter := MatchFormatter{
+		ID:       m.ID,
+		Date:     m.Date,
+		Time:     m.Time,
+		HomeTeam: formatTeam(m.HomeTeam.AsOf(kickoff), urls),
+		AwayTeam: formatTeam(m.AwayTeam.AsOf(kickoff), urls),
+	}
+
+	if m.Venue != nil {
+		formatter.Venue = &VenueFormatter{
+			ID:   m.Venue.ID,
+			Name: m.Venue.Name,
+		}
+	}
+
+	return formatter
+}
+
+func formatTeam(t team.Team, urls team.URLResolver) TeamFormatter {
+	return TeamFormatter{
+		ID:   t.ID,
+		Name: t.Name,
+		Logo: team.LogoURL(t.Logo, media.VariantOriginal, urls),
 	}
 }
 
 // 🔥 Tambahan untuk list
-func FormatMatches(matches []Match) []MatchFormatter {
+func FormatMatches(matches []Match, urls team.URLResolver) []MatchFormatter {
 	formatted := []MatchFormatter{}
 	for _, m := range matches {
-		formatted = append(formatted, FormatMatch(m))
+		formatted = append(formatted, FormatMatch(m, urls))
+	}
+	return formatted
+}
+
+type TrashedMatchFormatter struct {
+	MatchFormatter
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+func FormatTrashedMatches(matches []Match, urls team.URLResolver) []TrashedMatchFormatter {
+	formatted := []TrashedMatchFormatter{}
+	for _, m := range matches {
+		formatted = append(formatted, TrashedMatchFormatter{FormatMatch(m, urls), m.DeletedAt.Time})
 	}
 	return formatted
 }
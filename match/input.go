@@ -1,15 +1,24 @@
 package match
 
+import "footballteam/audit"
+
+// VenueID kosong berarti memakai kandang tim tuan rumah.
 type CreateMatchInput struct {
-	Date       string `json:"date" binding:"required"`
-	Time       string `json:"time" binding:"required"`
-	HomeTeamID int    `json:"home_team_id" binding:"required"`
-	AwayTeamID int    `json:"away_team_id" binding:"required"`
+	Date       string      `json:"date" binding:"required"`
+	Time       string      `json:"time" binding:"required"`
+	HomeTeamID int         `json:"home_team_id" binding:"required"`
+	AwayTeamID int         `json:"away_team_id" binding:"required"`
+	VenueID    *int        `json:"venue_id"`
+	Actor      audit.Actor `json:"-"`
 }
 
+// UpdateMatchInput: jika VenueID kosong, venue lama dipertahankan selama tim
+// tuan rumah tidak berubah; jika berubah, dipakai kandang tim tuan rumah baru.
 type UpdateMatchInput struct {
-	Date       string `json:"date" binding:"required"`
-	Time       string `json:"time" binding:"required"`
-	HomeTeamID int    `json:"home_team_id" binding:"required"`
-	AwayTeamID int    `json:"away_team_id" binding:"required"`
+	Date       string      `json:"date" binding:"required"`
+	Time       string      `json:"time" binding:"required"`
+	HomeTeamID int         `json:"home_team_id" binding:"required"`
+	AwayTeamID int         `json:"away_team_id" binding:"required"`
+	VenueID    *int        `json:"venue_id"`
+	Actor      audit.Actor `json:"-"`
 }
@@ -1,16 +1,36 @@
 package match
 
 import (
+	"errors"
+	"time"
+
+	"footballteam/team"
+	"footballteam/venue"
+
 	"gorm.io/gorm"
 )
 
+const (
+	dateLayout = "2006-01-02"
+	timeLayout = "15:04"
+)
+
 type Repository interface {
 	FindBySchedule(date, time string, homeTeamID, awayTeamID int) (Match, error)
 	FindAll() ([]Match, error)
 	FindByID(id int) (Match, error)
+	FindUpcomingByTeam(teamID int, from time.Time, limit int) ([]Match, error)
 	Create(match Match) (Match, error)
 	Update(match Match) (Match, error)
 	Delete(match Match) error
+	FindTrashed() ([]Match, error)
+	FindTrashedByID(id int) (Match, error)
+	Restore(match Match) (Match, error)
+	Purge(match Match) error
+	FindResultIDs(matchID int, includeTrashed bool) ([]int, error)
+	FindHomeVenueID(teamID int) (*int, error)
+	VenueExists(venueID int) (bool, error)
+	FindByVenueBetween(venueID int, fromDate, toDate string, excludeID int) ([]Match, error)
 }
 
 type repository struct {
@@ -34,6 +54,9 @@ func (r *repository) FindAll() ([]Match, error) {
 	err := r.db.
 		Preload("HomeTeam").
 		Preload("AwayTeam").
+		Preload("HomeTeam.Versions").
+		Preload("AwayTeam.Versions").
+		Preload("Venue").
 		Find(&matches).Error
 	return matches, err
 }
@@ -43,21 +66,138 @@ func (r *repository) FindByID(id int) (Match, error) {
 	err := r.db.
 		Preload("HomeTeam").
 		Preload("AwayTeam").
+		Preload("HomeTeam.Versions").
+		Preload("AwayTeam.Versions").
+		Preload("Venue").
 		First(&match, id).Error
 	return match, err
 }
 
+// FindUpcomingByTeam mengambil jadwal kandang maupun tandang mulai dari
+// waktu from, diurutkan dari yang paling dekat.
+func (r *repository) FindUpcomingByTeam(teamID int, from time.Time, limit int) ([]Match, error) {
+	var matches []Match
+	date, clock := from.Format(dateLayout), from.Format(timeLayout)
+
+	err := r.db.
+		Preload("HomeTeam").
+		Preload("AwayTeam").
+		Preload("HomeTeam.Versions").
+		Preload("AwayTeam.Versions").
+		Preload("Venue").
+		Where("home_team_id = ? OR away_team_id = ?", teamID, teamID).
+		Where("date > ? OR (date = ? AND time >= ?)", date, date, clock).
+		Order("date asc").Order("time asc").
+		Limit(limit).
+		Find(&matches).Error
+	return matches, err
+}
+
 func (r *repository) Create(match Match) (Match, error) {
 	err := r.db.Create(&match).Error
 	return match, err
 }
 
+// Update mengosongkan relasi yang sudah di-preload sebelum menyimpan; jika
+// tidak, gorm mengisi ulang HomeTeamID, AwayTeamID, dan VenueID dari relasi
+// lama. Data dimuat ulang supaya relasi di hasil sesuai ID yang baru.
 func (r *repository) Update(match Match) (Match, error) {
-	err := r.db.Save(&match).Error
-	return match, err
+	match.HomeTeam = team.Team{}
+	match.AwayTeam = team.Team{}
+	match.Venue = nil
+
+	if err := r.db.Save(&match).Error; err != nil {
+		return match, err
+	}
+	return r.FindByID(match.ID)
 }
 
 func (r *repository) Delete(match Match) error {
 	// Soft delete otomatis karena pakai gorm.Model (DeletedAt)
 	return r.db.Delete(&match).Error
 }
+
+// FindTrashed mengambil data yang sudah di-soft delete, terbaru dulu. Tim
+// tetap dimuat walaupun ikut terhapus supaya nama tim tetap tampil.
+func (r *repository) FindTrashed() ([]Match, error) {
+	var matches []Match
+	err := r.db.Unscoped().
+		Preload("HomeTeam", unscoped).
+		Preload("AwayTeam", unscoped).
+		Preload("HomeTeam.Versions").
+		Preload("AwayTeam.Versions").
+		Preload("Venue", unscoped).
+		Where("deleted_at IS NOT NULL").
+		Order("deleted_at desc").
+		Find(&matches).Error
+	return matches, err
+}
+
+func (r *repository) FindTrashedByID(id int) (Match, error) {
+	var match Match
+	err := r.db.Unscoped().
+		Preload("HomeTeam", unscoped).
+		Preload("AwayTeam", unscoped).
+		Preload("HomeTeam.Versions").
+		Preload("AwayTeam.Versions").
+		Preload("Venue", unscoped).
+		Where("deleted_at IS NOT NULL").
+		First(&match, id).Error
+	return match, err
+}
+
+func (r *repository) Restore(match Match) (Match, error) {
+	err := r.db.Unscoped().Model(&match).Update("deleted_at", nil).Error
+	return match, err
+}
+
+// Purge menghapus permanen, tidak bisa di-restore lagi.
+func (r *repository) Purge(match Match) error {
+	return r.db.Unscoped().Delete(&match).Error
+}
+
+func unscoped(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
+
+// FindResultIDs memakai tabel match_results langsung karena package
+// match_result bergantung ke package match.
+func (r *repository) FindResultIDs(matchID int, includeTrashed bool) ([]int, error) {
+	query := r.db.Table("match_results").Where("match_id = ?", matchID)
+	if !includeTrashed {
+		query = query.Where("deleted_at IS NULL")
+	}
+
+	var ids []int
+	err := query.Order("id").Pluck("id", &ids).Error
+	return ids, err
+}
+
+// FindHomeVenueID mengembalikan nil jika tim tidak punya kandang atau tidak
+// ditemukan.
+func (r *repository) FindHomeVenueID(teamID int) (*int, error) {
+	var homeTeam team.Team
+	err := r.db.Select("id", "home_venue_id").First(&homeTeam, teamID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return homeTeam.HomeVenueID, err
+}
+
+func (r *repository) VenueExists(venueID int) (bool, error) {
+	var count int64
+	err := r.db.Model(&venue.Venue{}).Where("id = ?", venueID).Count(&count).Error
+	return count > 0, err
+}
+
+// FindByVenueBetween mengambil pertandingan aktif di venue pada rentang
+// tanggal (inklusif), selain excludeID.
+func (r *repository) FindByVenueBetween(venueID int, fromDate, toDate string, excludeID int) ([]Match, error) {
+	var matches []Match
+	err := r.db.
+		Where("venue_id = ? AND id <> ?", venueID, excludeID).
+		Where("date BETWEEN ? AND ?", fromDate, toDate).
+		Order("date asc").Order("time asc").
+		Find(&matches).Error
+	return matches, err
+}
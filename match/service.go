@@ -1,21 +1,55 @@
 package match
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"footballteam/audit"
+	"footballteam/helper"
+)
+
+const auditEntityType = "match"
+
+// DefaultVenueClashWindow adalah jarak minimal antar kick-off di venue yang
+// sama.
+const DefaultVenueClashWindow = 3 * time.Hour
+
+var (
+	ErrScheduleTaken   = errors.New("pertandingan dengan jadwal, jam, dan tim yang sama sudah terdaftar")
+	ErrInvalidSchedule = errors.New("date must use YYYY-MM-DD and time must use HH:MM")
+	ErrVenueNotFound   = errors.New("venue not found")
+	ErrVenueClash      = errors.New("venue already hosts another match within the clash window")
+)
 
 type Service interface {
 	FindAll() ([]Match, error)
 	FindByID(id int) (Match, error)
+	GetUpcomingByTeam(teamID int, limit int) ([]Match, error)
 	CreateMatch(input CreateMatchInput) (Match, error)
 	UpdateMatch(id int, input UpdateMatchInput) (Match, error)
-	DeleteMatch(id int) error
+	DeleteMatch(id int, actor audit.Actor) error
+	GetTrashedMatches() ([]Match, error)
+	RestoreMatch(id int, actor audit.Actor) (Match, error)
+	PurgeMatch(id int, actor audit.Actor) error
+}
+
+type Config struct {
+	VenueClashWindow time.Duration
 }
 
 type service struct {
-	repository Repository
+	repository   Repository
+	auditService audit.Service
+	config       Config
 }
 
-func NewService(repository Repository) *service {
-	return &service{repository}
+func NewService(repository Repository, auditService audit.Service, config Config) *service {
+	if config.VenueClashWindow <= 0 {
+		config.VenueClashWindow = DefaultVenueClashWindow
+	}
+
+	return &service{repository, auditService, config}
 }
 
 func (s *service) FindAll() ([]Match, error) {
@@ -26,11 +60,23 @@ func (s *service) FindByID(id int) (Match, error) {
 	return s.repository.FindByID(id)
 }
 
+func (s *service) GetUpcomingByTeam(teamID int, limit int) ([]Match, error) {
+	return s.repository.FindUpcomingByTeam(teamID, time.Now(), limit)
+}
+
 func (s *service) CreateMatch(input CreateMatchInput) (Match, error) {
 	// Cek apakah match sudah ada dengan jadwal yang sama
 	existing, err := s.repository.FindBySchedule(input.Date, input.Time, input.HomeTeamID, input.AwayTeamID)
 	if err == nil && existing.ID != 0 {
-		return Match{}, errors.New("pertandingan dengan jadwal, jam, dan tim yang sama sudah terdaftar")
+		return Match{}, ErrScheduleTaken
+	}
+
+	venueID, err := s.resolveVenue(input.VenueID, input.HomeTeamID)
+	if err != nil {
+		return Match{}, err
+	}
+	if err := s.checkVenueClash(venueID, input.Date, input.Time, 0); err != nil {
+		return Match{}, err
 	}
 
 	match := Match{
@@ -38,6 +84,7 @@ func (s *service) CreateMatch(input CreateMatchInput) (Match, error) {
 		Time:       input.Time,
 		HomeTeamID: input.HomeTeamID,
 		AwayTeamID: input.AwayTeamID,
+		VenueID:    venueID,
 	}
 
 	newMatch, err := s.repository.Create(match)
@@ -45,6 +92,8 @@ func (s *service) CreateMatch(input CreateMatchInput) (Match, error) {
 		return newMatch, err
 	}
 
+	s.auditService.Record(input.Actor, audit.ActionCreate, auditEntityType, newMatch.ID, nil, newMatch)
+
 	return newMatch, nil
 }
 
@@ -53,30 +102,171 @@ func (s *service) UpdateMatch(id int, input UpdateMatchInput) (Match, error) {
 	if err != nil {
 		return match, errors.New("match not found")
 	}
+	before := match
 
 	// Cek duplikasi (kecuali dirinya sendiri)
 	existing, err := s.repository.FindBySchedule(input.Date, input.Time, input.HomeTeamID, input.AwayTeamID)
 	if err == nil && existing.ID != 0 && existing.ID != id {
-		return Match{}, errors.New("pertandingan dengan jadwal, jam, dan tim yang sama sudah terdaftar")
+		return Match{}, ErrScheduleTaken
+	}
+
+	venueID := match.VenueID
+	if input.VenueID != nil || venueID == nil || input.HomeTeamID != match.HomeTeamID {
+		venueID, err = s.resolveVenue(input.VenueID, input.HomeTeamID)
+		if err != nil {
+			return match, err
+		}
+	}
+	if err := s.checkVenueClash(venueID, input.Date, input.Time, match.ID); err != nil {
+		return match, err
 	}
 
 	match.Date = input.Date
 	match.Time = input.Time
 	match.HomeTeamID = input.HomeTeamID
 	match.AwayTeamID = input.AwayTeamID
+	match.VenueID = venueID
 
 	updated, err := s.repository.Update(match)
 	if err != nil {
 		return updated, err
 	}
 
+	s.auditService.Record(input.Actor, audit.ActionUpdate, auditEntityType, updated.ID, before, updated)
+
 	return updated, nil
 }
 
-func (s *service) DeleteMatch(id int) error {
+func (s *service) DeleteMatch(id int, actor audit.Actor) error {
 	match, err := s.repository.FindByID(id)
 	if err != nil {
 		return err
 	}
-	return s.repository.Delete(match)
+
+	resultIDs, err := s.repository.FindResultIDs(match.ID, false)
+	if err != nil {
+		return err
+	}
+	if err := helper.NewInUseError(map[string][]int{"match_results": resultIDs}); err != nil {
+		return err
+	}
+
+	if err := s.repository.Delete(match); err != nil {
+		return err
+	}
+
+	s.auditService.Record(actor, audit.ActionDelete, auditEntityType, match.ID, match, nil)
+
+	return nil
+}
+
+func (s *service) GetTrashedMatches() ([]Match, error) {
+	return s.repository.FindTrashed()
+}
+
+// RestoreMatch gagal jika jadwal yang sama sudah dibuat ulang sejak dihapus
+// atau venue-nya sudah dipakai pertandingan lain di sekitar kick-off.
+func (s *service) RestoreMatch(id int, actor audit.Actor) (Match, error) {
+	match, err := s.repository.FindTrashedByID(id)
+	if err != nil {
+		return match, err
+	}
+
+	existing, err := s.repository.FindBySchedule(match.Date, match.Time, match.HomeTeamID, match.AwayTeamID)
+	if err == nil && existing.ID != 0 {
+		return match, ErrScheduleTaken
+	}
+	if err := s.checkVenueClash(match.VenueID, match.Date, match.Time, match.ID); err != nil {
+		return match, err
+	}
+
+	restored, err := s.repository.Restore(match)
+	if err != nil {
+		return restored, err
+	}
+
+	s.auditService.Record(actor, audit.ActionRestore, auditEntityType, restored.ID, nil, nil)
+
+	return restored, nil
+}
+
+func (s *service) PurgeMatch(id int, actor audit.Actor) error {
+	match, err := s.repository.FindTrashedByID(id)
+	if err != nil {
+		return err
+	}
+
+	resultIDs, err := s.repository.FindResultIDs(match.ID, true)
+	if err != nil {
+		return err
+	}
+	if err := helper.NewInUseError(map[string][]int{"match_results": resultIDs}); err != nil {
+		return err
+	}
+
+	if err := s.repository.Purge(match); err != nil {
+		return err
+	}
+
+	s.auditService.Record(actor, audit.ActionPurge, auditEntityType, match.ID, match, nil)
+
+	return nil
+}
+
+// resolveVenue memakai kandang tim tuan rumah jika venue tidak diisi.
+func (s *service) resolveVenue(venueID *int, homeTeamID int) (*int, error) {
+	if venueID == nil || *venueID == 0 {
+		return s.repository.FindHomeVenueID(homeTeamID)
+	}
+
+	exists, err := s.repository.VenueExists(*venueID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrVenueNotFound
+	}
+
+	return venueID, nil
+}
+
+// checkVenueClash menolak jadwal yang formatnya salah dan kick-off yang
+// jaraknya kurang dari config.VenueClashWindow dari pertandingan lain di venue
+// yang sama.
+func (s *service) checkVenueClash(venueID *int, date, clock string, excludeID int) error {
+	// Jadwal divalidasi dulu supaya match tanpa venue juga ditolak jika
+	// formatnya salah
+	kickoff, err := time.ParseInLocation(dateLayout+" "+timeLayout, date+" "+clock, time.Local)
+	if err != nil {
+		return ErrInvalidSchedule
+	}
+	if venueID == nil {
+		return nil
+	}
+
+	window := s.config.VenueClashWindow
+	from := kickoff.Add(-window).Format(dateLayout)
+	to := kickoff.Add(window).Format(dateLayout)
+
+	candidates, err := s.repository.FindByVenueBetween(*venueID, from, to, excludeID)
+	if err != nil {
+		return err
+	}
+
+	for _, other := range candidates {
+		otherKickoff, err := time.ParseInLocation(dateLayout+" "+timeLayout, other.Date+" "+other.Time, time.Local)
+		if err != nil {
+			continue
+		}
+
+		gap := kickoff.Sub(otherKickoff)
+		if gap < 0 {
+			gap = -gap
+		}
+		if gap < window {
+			return fmt.Errorf("%w (match %d at %s %s)", ErrVenueClash, other.ID, other.Date, other.Time)
+		}
+	}
+
+	return nil
 }
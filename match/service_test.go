@@ -0,0 +1,55 @@
+package match
+
+import (
+	"errors"
+	"testing"
+)
+
+type fakeRepository struct {
+	Repository
+	atVenue []Match
+}
+
+func (r *fakeRepository) FindByVenueBetween(venueID int, fromDate, toDate string, excludeID int) ([]Match, error) {
+	var matches []Match
+	for _, m := range r.atVenue {
+		if m.ID != excludeID && m.Date >= fromDate && m.Date <= toDate {
+			matches = append(matches, m)
+		}
+	}
+	return matches, nil
+}
+
+func TestCheckVenueClash(t *testing.T) {
+	venueID := 1
+	repository := &fakeRepository{atVenue: []Match{{ID: 7, Date: "2025-08-10", Time: "19:00"}}}
+	s := NewService(repository, nil, Config{})
+
+	tests := []struct {
+		name      string
+		venueID   *int
+		date      string
+		clock     string
+		excludeID int
+		wantErr   error
+	}{
+		{"no venue", nil, "2025-08-10", "19:00", 0, nil},
+		{"no venue, invalid date", nil, "10-08-2025", "19:00", 0, ErrInvalidSchedule},
+		{"no venue, invalid time", nil, "2025-08-10", "7pm", 0, ErrInvalidSchedule},
+		{"invalid date", &venueID, "2025-02-30", "19:00", 0, ErrInvalidSchedule},
+		{"same kickoff", &venueID, "2025-08-10", "19:00", 0, ErrVenueClash},
+		{"inside window", &venueID, "2025-08-10", "21:59", 0, ErrVenueClash},
+		{"window edge", &venueID, "2025-08-10", "22:00", 0, nil},
+		{"before, window edge", &venueID, "2025-08-10", "16:00", 0, nil},
+		{"same match excluded", &venueID, "2025-08-10", "19:00", 7, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.checkVenueClash(tt.venueID, tt.date, tt.clock, tt.excludeID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("checkVenueClash error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package match_result
+
+import (
+	"footballteam/media"
+	"footballteam/team"
+)
+
+const (
+	OutcomeWin  = "W"
+	OutcomeDraw = "D"
+	OutcomeLoss = "L"
+)
+
+// Outcome menghitung hasil pertandingan dari sudut pandang teamID
+// berdasarkan skor akhir.
+func Outcome(result MatchResult, teamID int) string {
+	teamScore, opponentScore := result.HomeScore, result.AwayScore
+	if result.Match.AwayTeamID == teamID {
+		teamScore, opponentScore = result.AwayScore, result.HomeScore
+	}
+
+	switch {
+	case teamScore > opponentScore:
+		return OutcomeWin
+	case teamScore < opponentScore:
+		return OutcomeLoss
+	}
+	return OutcomeDraw
+}
+
+// Form membuat string performa seperti "WWDLW" dari hasil yang diurutkan
+// dari paling baru (urutan FindRecentByTeam). String dibaca dari kiri ke
+// kanan dengan pertandingan terbaru di paling kanan.
+func Form(results []MatchResult, teamID int) string {
+	form := make([]byte, 0, len(results))
+	for i := len(results) - 1; i >= 0; i-- {
+		form = append(form, Outcome(results[i], teamID)...)
+	}
+	return string(form)
+}
+
+type TeamResultFormatter struct {
+	MatchID      int    `json:"match_id"`
+	Date         string `json:"date"`
+	Time         string `json:"time"`
+	HomeTeam     string `json:"home_team"`
+	AwayTeam     string `json:"away_team"`
+	HomeTeamLogo string `json:"home_team_logo"`
+	AwayTeamLogo string `json:"away_team_logo"`
+	HomeScore    int    `json:"home_score"`
+	AwayScore    int    `json:"away_score"`
+	Outcome      string `json:"outcome"` // W / D / L dari sudut pandang tim
+}
+
+// FormatTeamResults menampilkan nama dan logo tim yang berlaku pada tanggal
+// pertandingan.
+func FormatTeamResults(results []MatchResult, teamID int, urls team.URLResolver) []TeamResultFormatter {
+	formatted := []TeamResultFormatter{}
+	for _, r := range results {
+		kickoff := r.Match.Kickoff()
+		home, away := r.Match.HomeTeam.AsOf(kickoff), r.Match.AwayTeam.AsOf(kickoff)
+		formatted = append(formatted, TeamResultFormatter{
+			MatchID:      r.MatchID,
+			Date:         r.Match.Date,
+			Time:         r.Match.Time,
+			HomeTeam:     home.Name,
+			AwayTeam:     away.Name,
+			HomeTeamLogo: team.LogoURL(home.Logo, media.VariantOriginal, urls),
+			AwayTeamLogo: team.LogoURL(away.Logo, media.VariantOriginal, urls),
+			HomeScore:    r.HomeScore,
+			AwayScore:    r.AwayScore,
+			Outcome:      Outcome(r, teamID),
+		})
+	}
+	return formatted
+}
@@ -2,6 +2,10 @@ package match_result
 
 import (
 	"sort"
+	"time"
+
+	"footballteam/media"
+	"footballteam/team"
 )
 
 // Formatter untuk Goal per MatchResult
@@ -29,6 +33,8 @@ type MatchResultReportFormatter struct {
 	Time          string   `json:"time"`
 	HomeTeam      string   `json:"home_team"`
 	AwayTeam      string   `json:"away_team"`
+	HomeTeamLogo  string   `json:"home_team_logo"`
+	AwayTeamLogo  string   `json:"away_team_logo"`
 	HomeScore     int      `json:"home_score"`
 	AwayScore     int      `json:"away_score"`
 	Status        string   `json:"status"`          // Home Menang / Away Menang / Draw
@@ -59,8 +65,9 @@ func FormatMatchResult(m MatchResult, playerMap map[int]string) MatchResultForma
 	}
 }
 
-// FormatMatchResultReport untuk report lengkap
-func FormatMatchResultReport(results []MatchResult) []MatchResultReportFormatter {
+// FormatMatchResultReport untuk report lengkap. Nama dan logo tim diambil
+// dari versi yang berlaku pada tanggal pertandingan.
+func FormatMatchResultReport(results []MatchResult, urls team.URLResolver) []MatchResultReportFormatter {
 	report := []MatchResultReportFormatter{}
 
 	// total kemenangan per tim
@@ -101,13 +108,16 @@ func FormatMatchResultReport(results []MatchResult) []MatchResultReportFormatter
 		}
 
 		m := r.Match // pastikan MatchResult memiliki relasi Match
+		home, away := m.HomeTeam.AsOf(m.Kickoff()), m.AwayTeam.AsOf(m.Kickoff())
 
 		report = append(report, MatchResultReportFormatter{
 			MatchID:       r.MatchID,
 			Date:          m.Date,
 			Time:          m.Time,
-			HomeTeam:      m.HomeTeam.Name,
-			AwayTeam:      m.AwayTeam.Name,
+			HomeTeam:      home.Name,
+			AwayTeam:      away.Name,
+			HomeTeamLogo:  team.LogoURL(home.Logo, media.VariantOriginal, urls),
+			AwayTeamLogo:  team.LogoURL(away.Logo, media.VariantOriginal, urls),
 			HomeScore:     r.HomeScore,
 			AwayScore:     r.AwayScore,
 			Status:        r.Status,
@@ -127,3 +137,22 @@ func FormatMatchResultReport(results []MatchResult) []MatchResultReportFormatter
 
 	return report
 }
+
+type TrashedMatchResultFormatter struct {
+	MatchResultFormatter
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// FormatTrashedMatchResults memakai nama pemain dari relasi Goals.Player yang
+// sudah di-preload (termasuk pemain yang ikut terhapus).
+func FormatTrashedMatchResults(results []MatchResult) []TrashedMatchResultFormatter {
+	formatted := []TrashedMatchResultFormatter{}
+	for _, r := range results {
+		playerMap := make(map[int]string)
+		for _, g := range r.Goals {
+			playerMap[g.PlayerID] = g.Player.Name
+		}
+		formatted = append(formatted, TrashedMatchResultFormatter{FormatMatchResult(r, playerMap), r.DeletedAt.Time})
+	}
+	return formatted
+}
@@ -1,5 +1,7 @@
 package match_result
 
+import "footballteam/audit"
+
 type CreateGoalInput struct {
 	PlayerID int `json:"player_id" binding:"required"`
 	TeamID   int `json:"team_id" binding:"required"`
@@ -12,4 +14,5 @@ type CreateMatchResultInput struct {
 	AwayScore int               `json:"away_score"`
 	Status    string            `json:"status"`
 	Goals     []CreateGoalInput `json:"goals"`
+	Actor     audit.Actor       `json:"-"`
 }
@@ -8,6 +8,12 @@ type Repository interface {
 	FindAll() ([]MatchResult, error)
 	FindByMatchID(matchID int) (MatchResult, error)
 	FindAllWithRelations() ([]MatchResult, error)
+	FindRecentByTeam(teamID int, limit int) ([]MatchResult, error)
+	Delete(result MatchResult) error
+	FindTrashed() ([]MatchResult, error)
+	FindTrashedByID(id int) (MatchResult, error)
+	Restore(result MatchResult) (MatchResult, error)
+	Purge(result MatchResult) error
 }
 
 type repository struct {
@@ -32,22 +38,99 @@ func (r *repository) Create(matchResult MatchResult) (MatchResult, error) {
 
 func (r *repository) FindByID(id int) (MatchResult, error) {
 	var result MatchResult
-	err := r.db.Preload("Goals.Player").First(&result, id).Error
+	err := r.db.Preload("Goals.Player", unscoped).First(&result, id).Error
 	return result, err
 }
 
 func (r *repository) FindAll() ([]MatchResult, error) {
 	var results []MatchResult
-	err := r.db.Preload("Goals.Player").Find(&results).Error
+	err := r.db.Preload("Goals.Player", unscoped).Find(&results).Error
 	return results, err
 }
 
 func (r *repository) FindAllWithRelations() ([]MatchResult, error) {
     var results []MatchResult
-    err := r.db.Preload("Goals.Player").
+    err := r.db.Preload("Goals.Player", unscoped).
                Preload("Match.HomeTeam").
                Preload("Match.AwayTeam").
+               Preload("Match.HomeTeam.Versions").
+               Preload("Match.AwayTeam.Versions").
                Find(&results).Error
     return results, err
 }
 
+
+// FindRecentByTeam mengambil hasil pertandingan terbaru sebuah tim, baik
+// sebagai tuan rumah maupun tamu, diurutkan dari yang paling baru.
+func (r *repository) FindRecentByTeam(teamID int, limit int) ([]MatchResult, error) {
+	var results []MatchResult
+	err := r.db.Preload("Goals.Player", unscoped).
+		Preload("Match.HomeTeam").
+		Preload("Match.AwayTeam").
+		Preload("Match.HomeTeam.Versions").
+		Preload("Match.AwayTeam.Versions").
+		Joins("JOIN matches ON matches.id = match_results.match_id").
+		Where("matches.home_team_id = ? OR matches.away_team_id = ?", teamID, teamID).
+		Order("matches.date desc").Order("matches.time desc").
+		Limit(limit).
+		Find(&results).Error
+	return results, err
+}
+
+// Delete hanya menandai match result; goal tetap tersimpan dan ikut kembali
+// saat di-restore.
+func (r *repository) Delete(result MatchResult) error {
+	return r.db.Delete(&result).Error
+}
+
+func (r *repository) FindTrashed() ([]MatchResult, error) {
+	var results []MatchResult
+	err := r.db.Unscoped().
+		Preload("Goals.Player", unscoped).
+		Preload("Match", unscoped).
+		Preload("Match.HomeTeam", unscoped).
+		Preload("Match.AwayTeam", unscoped).
+		Preload("Match.HomeTeam.Versions").
+		Preload("Match.AwayTeam.Versions").
+		Where("deleted_at IS NOT NULL").
+		Order("deleted_at desc").
+		Find(&results).Error
+	return results, err
+}
+
+func (r *repository) FindTrashedByID(id int) (MatchResult, error) {
+	var result MatchResult
+	err := r.db.Unscoped().
+		Preload("Goals.Player", unscoped).
+		Where("deleted_at IS NOT NULL").
+		First(&result, id).Error
+	return result, err
+}
+
+// Restore ikut mengembalikan goal yang di-soft delete bersama match result
+// saat tim dihapus dengan policy cascade.
+func (r *repository) Restore(result MatchResult) (MatchResult, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&Goal{}).Where("match_result_id = ?", result.ID).Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Model(&result).Update("deleted_at", nil).Error
+	})
+	return result, err
+}
+
+// Purge menghapus permanen match result beserta semua goal-nya.
+func (r *repository) Purge(result MatchResult) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("match_result_id = ?", result.ID).Delete(&Goal{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&result).Error
+	})
+}
+
+// unscoped dipakai supaya pencetak gol yang sudah di-soft delete tetap tampil
+// di hasil pertandingan.
+func unscoped(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
@@ -1,7 +1,9 @@
 package match_result
 
 import (
+	"errors"
 	"fmt"
+	"footballteam/audit"
 	"footballteam/match"
 	"footballteam/player"
 	"time"
@@ -9,83 +11,107 @@ import (
 	"gorm.io/gorm"
 )
 
+const auditEntityType = "match_result"
+
+var (
+	ErrResultExists       = errors.New("match result for this match already exists")
+	ErrGoalTeamNotInMatch = errors.New("goal team must be the home or away team of the match")
+)
+
 type Service interface {
 	Create(input CreateMatchResultInput) (MatchResult, error)
 	FindAll() ([]MatchResult, error)
 	FindByID(id int) (MatchResult, error)
-	GetMatchResultsReport() ([]MatchResultReportFormatter, error)
+	GetMatchResultsReport() ([]MatchResult, error)
+	GetRecentByTeam(teamID int, limit int) ([]MatchResult, error)
+	Delete(id int, actor audit.Actor) error
+	GetTrashed() ([]MatchResult, error)
+	Restore(id int, actor audit.Actor) (MatchResult, error)
+	Purge(id int, actor audit.Actor) error
 }
 
 type service struct {
-	repository     Repository
-	playerService  player.Service // <-- tambahkan ini
-	matchService   match.Service  // optional, untuk validasi match exist
+	repository    Repository
+	playerService player.Service // <-- tambahkan ini
+	matchService  match.Service  // optional, untuk validasi match exist
+	auditService  audit.Service
 }
 
-func NewService(repo Repository, pService player.Service, mService match.Service) Service {
+func NewService(repo Repository, pService player.Service, mService match.Service, auditService audit.Service) Service {
 	return &service{
 		repository:    repo,
 		playerService: pService,
 		matchService:  mService,
+		auditService:  auditService,
 	}
 }
 
 func (s *service) Create(input CreateMatchResultInput) (MatchResult, error) {
-    // Cek apakah match exist
-    _, err := s.matchService.FindByID(input.MatchID)
-    if err != nil {
-        return MatchResult{}, fmt.Errorf("match with ID %d not found", input.MatchID)
-    }
-
-    // Cek apakah match result untuk match yang sama sudah ada
-    existing, err := s.repository.FindByMatchID(input.MatchID)
-    if err != nil && err != gorm.ErrRecordNotFound {
-        return MatchResult{}, err
-    }
-    if err == nil {
-        return existing, fmt.Errorf("match result for match ID %d already exists", input.MatchID)
-    }
-
-    // Buat MatchResult baru
-    matchResult := MatchResult{
-        MatchID:   input.MatchID,
-        HomeScore: input.HomeScore,
-        AwayScore: input.AwayScore,
-        Status:    input.Status,
-        CreatedAt: time.Now(),
-        UpdatedAt: time.Now(),
-    }
-
-   // Validasi goals
-    for _, g := range input.Goals {
-        player, err := s.playerService.GetPlayerByID(g.PlayerID)
-        if err != nil {
-            return MatchResult{}, fmt.Errorf("player with ID %d not found", g.PlayerID)
-        }
-
-        // Cek apakah player berada di tim yang sesuai
-        if player.TeamID != g.TeamID {
-            return MatchResult{}, fmt.Errorf("player %s (ID %d) does not belong to team %d", player.Name, player.ID, g.TeamID)
-        }
-
-        // Jika valid, masukkan goal
-        matchResult.Goals = append(matchResult.Goals, Goal{
-            PlayerID:  g.PlayerID,
-            TeamID:    g.TeamID,
-            Minute:    g.Minute,
-            CreatedAt: time.Now(),
-            UpdatedAt: time.Now(),
-        })
-    }
-
-    result, err := s.repository.Create(matchResult)
-    if err != nil {
-        return result, err
-    }
-
-    return result, nil
-}
+	// Cek apakah match exist
+	fixture, err := s.matchService.FindByID(input.MatchID)
+	if err != nil {
+		return MatchResult{}, fmt.Errorf("match with ID %d not found", input.MatchID)
+	}
 
+	// Cek apakah match result untuk match yang sama sudah ada
+	existing, err := s.repository.FindByMatchID(input.MatchID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return MatchResult{}, err
+	}
+	if err == nil {
+		return existing, fmt.Errorf("match result for match ID %d already exists", input.MatchID)
+	}
+
+	// Buat MatchResult baru
+	matchResult := MatchResult{
+		MatchID:   input.MatchID,
+		HomeScore: input.HomeScore,
+		AwayScore: input.AwayScore,
+		Status:    input.Status,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	// Validasi goals
+	for _, g := range input.Goals {
+		if g.TeamID != fixture.HomeTeamID && g.TeamID != fixture.AwayTeamID {
+			return MatchResult{}, fmt.Errorf("%w: team %d did not play match %d", ErrGoalTeamNotInMatch, g.TeamID, fixture.ID)
+		}
+
+		player, err := s.playerService.GetPlayerByID(g.PlayerID)
+		if err != nil {
+			return MatchResult{}, fmt.Errorf("player with ID %d not found", g.PlayerID)
+		}
+
+		// Cek apakah player berada di tim yang sesuai pada tanggal match,
+		// bukan tim sekarang; pemain bisa sudah pindah sejak match dimainkan
+		teamID, err := s.playerService.GetTeamAsOf(player, fixture.Kickoff())
+		if err != nil {
+			return MatchResult{}, err
+		}
+		if teamID != g.TeamID {
+			return MatchResult{}, fmt.Errorf("player %s (ID %d) did not belong to team %d on %s", player.Name, player.ID, g.TeamID, fixture.Date)
+		}
+
+		// Jika valid, masukkan goal
+		matchResult.Goals = append(matchResult.Goals, Goal{
+			PlayerID:  g.PlayerID,
+			TeamID:    g.TeamID,
+			Minute:    g.Minute,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		})
+	}
+
+	result, err := s.repository.Create(matchResult)
+	if err != nil {
+		return result, err
+	}
+
+	s.auditService.Record(input.Actor, audit.ActionCreate, auditEntityType, result.ID, nil, result)
+
+	return result, nil
+}
 
 func (s *service) FindAll() ([]MatchResult, error) {
 	return s.repository.FindAll()
@@ -95,14 +121,71 @@ func (s *service) FindByID(id int) (MatchResult, error) {
 	return s.repository.FindByID(id)
 }
 
-func (s *service) GetMatchResultsReport() ([]MatchResultReportFormatter, error) {
-	// Ambil semua match result beserta relasi Match, Teams, dan Goals
-	results, err := s.repository.FindAllWithRelations()
+func (s *service) GetRecentByTeam(teamID int, limit int) ([]MatchResult, error) {
+	return s.repository.FindRecentByTeam(teamID, limit)
+}
+
+// GetMatchResultsReport mengambil semua match result beserta relasi Match,
+// Teams, dan Goals; diformat di handler dengan FormatMatchResultReport.
+func (s *service) GetMatchResultsReport() ([]MatchResult, error) {
+	return s.repository.FindAllWithRelations()
+}
+
+func (s *service) Delete(id int, actor audit.Actor) error {
+	result, err := s.repository.FindByID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repository.Delete(result); err != nil {
+		return err
+	}
+
+	s.auditService.Record(actor, audit.ActionDelete, auditEntityType, result.ID, result, nil)
+
+	return nil
+}
+
+func (s *service) GetTrashed() ([]MatchResult, error) {
+	return s.repository.FindTrashed()
+}
+
+// Restore gagal jika match yang sama sudah punya hasil baru sejak dihapus.
+func (s *service) Restore(id int, actor audit.Actor) (MatchResult, error) {
+	result, err := s.repository.FindTrashedByID(id)
 	if err != nil {
-		return nil, err
+		return result, err
+	}
+
+	_, err = s.repository.FindByMatchID(result.MatchID)
+	if err == nil {
+		return result, ErrResultExists
+	}
+	if err != gorm.ErrRecordNotFound {
+		return result, err
 	}
 
-	report := FormatMatchResultReport(results)
-	return report, nil
+	restored, err := s.repository.Restore(result)
+	if err != nil {
+		return restored, err
+	}
+
+	s.auditService.Record(actor, audit.ActionRestore, auditEntityType, restored.ID, nil, nil)
+
+	return restored, nil
 }
 
+func (s *service) Purge(id int, actor audit.Actor) error {
+	result, err := s.repository.FindTrashedByID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repository.Purge(result); err != nil {
+		return err
+	}
+
+	s.auditService.Record(actor, audit.ActionPurge, auditEntityType, result.ID, result, nil)
+
+	return nil
+}
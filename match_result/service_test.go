@@ -0,0 +1,102 @@
+package match_result
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"footballteam/audit"
+	"footballteam/match"
+	"footballteam/player"
+
+	"gorm.io/gorm"
+)
+
+type fakeRepository struct {
+	Repository
+	created []MatchResult
+}
+
+func (r *fakeRepository) FindByMatchID(matchID int) (MatchResult, error) {
+	return MatchResult{}, gorm.ErrRecordNotFound
+}
+
+func (r *fakeRepository) Create(result MatchResult) (MatchResult, error) {
+	result.ID = len(r.created) + 1
+	r.created = append(r.created, result)
+	return result, nil
+}
+
+type fakeMatchService struct {
+	match.Service
+	fixture match.Match
+}
+
+func (s fakeMatchService) FindByID(id int) (match.Match, error) {
+	if id != s.fixture.ID {
+		return match.Match{}, gorm.ErrRecordNotFound
+	}
+	return s.fixture, nil
+}
+
+// fakePlayerService menyimpan tim setiap pemain pada tanggal match.
+type fakePlayerService struct {
+	player.Service
+	teamAsOf map[int]int
+}
+
+func (s fakePlayerService) GetPlayerByID(id int) (player.Player, error) {
+	if _, ok := s.teamAsOf[id]; !ok {
+		return player.Player{}, gorm.ErrRecordNotFound
+	}
+	return player.Player{ID: id, Name: "Pemain", TeamID: 99}, nil
+}
+
+func (s fakePlayerService) GetTeamAsOf(p player.Player, date time.Time) (int, error) {
+	return s.teamAsOf[p.ID], nil
+}
+
+type fakeAuditService struct{ audit.Service }
+
+func (fakeAuditService) Record(audit.Actor, string, string, int, interface{}, interface{}) {}
+
+func TestCreateValidatesGoals(t *testing.T) {
+	fixture := match.Match{ID: 1, HomeTeamID: 10, AwayTeamID: 20, Date: "2024-08-10", Time: "19:00"}
+	// Pemain 1 di tim 10 dan pemain 2 di tim 20 pada tanggal match; pemain 3
+	// bermain untuk tim 30 yang tidak ikut bertanding
+	players := fakePlayerService{teamAsOf: map[int]int{1: 10, 2: 20, 3: 30}}
+
+	tests := []struct {
+		name    string
+		goals   []CreateGoalInput
+		wantErr error
+	}{
+		{"home and away scorers", []CreateGoalInput{{PlayerID: 1, TeamID: 10, Minute: 5}, {PlayerID: 2, TeamID: 20, Minute: 70}}, nil},
+		{"team did not play", []CreateGoalInput{{PlayerID: 3, TeamID: 30, Minute: 12}}, ErrGoalTeamNotInMatch},
+		{"valid goal before a team that did not play", []CreateGoalInput{{PlayerID: 1, TeamID: 10, Minute: 5}, {PlayerID: 3, TeamID: 30, Minute: 12}}, ErrGoalTeamNotInMatch},
+		{"scorer credited to the other side", []CreateGoalInput{{PlayerID: 1, TeamID: 20, Minute: 5}}, errAny},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := &fakeRepository{}
+			s := NewService(repository, players, fakeMatchService{fixture: fixture}, fakeAuditService{})
+
+			_, err := s.Create(CreateMatchResultInput{MatchID: fixture.ID, Goals: tt.goals})
+
+			switch {
+			case tt.wantErr == nil && err != nil:
+				t.Fatalf("Create error: %v", err)
+			case tt.wantErr == errAny && err == nil,
+				tt.wantErr != nil && tt.wantErr != errAny && !errors.Is(err, tt.wantErr):
+				t.Fatalf("Create error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil && len(repository.created) != 0 {
+				t.Errorf("match result created despite error: %+v", repository.created)
+			}
+		})
+	}
+}
+
+// errAny menandai kasus yang cukup gagal tanpa error tertentu.
+var errAny = errors.New("any error")
@@ -0,0 +1,185 @@
+package media
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+
+	"footballteam/storage"
+)
+
+var (
+	ErrTooLarge           = errors.New("file is larger than the allowed size")
+	ErrUnsupportedType    = errors.New("only JPEG and PNG images are allowed")
+	ErrDimensionsTooLarge = errors.New("image dimensions are too large")
+)
+
+const (
+	DefaultPrefix  = "logo"
+	DefaultMaxSize = 2 << 20 // 2 MB
+
+	// maxPixels membatasi ukuran gambar setelah di-decode supaya file kecil
+	// dengan dimensi raksasa tidak menghabiskan memori.
+	maxPixels   = 25_000_000
+	jpegQuality = 85
+)
+
+type Variant string
+
+const (
+	VariantOriginal  Variant = "original"
+	VariantMedium    Variant = "medium"
+	VariantThumbnail Variant = "thumbnail"
+)
+
+// variantSizes adalah sisi terpanjang tiap ukuran dalam piksel. Gambar yang
+// lebih kecil tidak diperbesar.
+var variantSizes = map[Variant]int{
+	VariantMedium:    256,
+	VariantThumbnail: 64,
+}
+
+var extensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+}
+
+// hashedName cocok dengan nama file yang dibuat SaveImage. Logo lama yang
+// diupload sebelum ada pipeline ini tidak punya varian.
+var hashedName = regexp.MustCompile(`^[0-9a-f]{64}\.(jpg|png)$`)
+
+type Service interface {
+	SaveImage(r io.Reader) (string, error)
+	Remove(key string) error
+}
+
+type Config struct {
+	Prefix  string // folder key di storage
+	MaxSize int64  // byte
+}
+
+type service struct {
+	storage storage.Storage
+	config  Config
+}
+
+func NewService(store storage.Storage, config Config) *service {
+	if config.Prefix == "" {
+		config.Prefix = DefaultPrefix
+	}
+	if config.MaxSize <= 0 {
+		config.MaxSize = DefaultMaxSize
+	}
+
+	return &service{store, config}
+}
+
+// SaveImage memvalidasi tipe gambar dari isinya (bukan dari ekstensi), lalu
+// menyimpan ukuran asli beserta varian medium dan thumbnail. Nama file diambil
+// dari hash isi, jadi gambar yang sama hanya disimpan sekali. Yang
+// dikembalikan adalah storage key ukuran asli.
+func (s *service) SaveImage(r io.Reader) (string, error) {
+	data, err := io.ReadAll(io.LimitReader(r, s.config.MaxSize+1))
+	if err != nil {
+		return "", err
+	}
+	if int64(len(data)) > s.config.MaxSize {
+		return "", ErrTooLarge
+	}
+
+	contentType := http.DetectContentType(data)
+	ext, ok := extensions[contentType]
+	if !ok {
+		return "", ErrUnsupportedType
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", ErrUnsupportedType
+	}
+	if config.Width*config.Height > maxPixels {
+		return "", ErrDimensionsTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return "", ErrUnsupportedType
+	}
+
+	sum := sha256.Sum256(data)
+	original := path.Join(s.config.Prefix, hex.EncodeToString(sum[:])+ext)
+
+	if err := s.putOnce(original, data, contentType); err != nil {
+		return "", err
+	}
+
+	for variant, size := range variantSizes {
+		var buf bytes.Buffer
+		resized := fit(img, size)
+		if contentType == "image/png" {
+			err = png.Encode(&buf, resized)
+		} else {
+			err = jpeg.Encode(&buf, resized, &jpeg.Options{Quality: jpegQuality})
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if err := s.putOnce(VariantPath(original, variant), buf.Bytes(), contentType); err != nil {
+			return "", err
+		}
+	}
+
+	return original, nil
+}
+
+// Remove menghapus gambar beserta semua variannya. File yang sudah tidak ada
+// diabaikan.
+func (s *service) Remove(original string) error {
+	paths := []string{original}
+	if hashedName.MatchString(path.Base(original)) {
+		for variant := range variantSizes {
+			paths = append(paths, VariantPath(original, variant))
+		}
+	}
+
+	for _, p := range paths {
+		if err := s.storage.Delete(p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// VariantPath mengembalikan key varian dari key ukuran asli, misalnya
+// "logo/<hash>_thumbnail.png". Logo lama tanpa varian dikembalikan apa
+// adanya.
+func VariantPath(original string, variant Variant) string {
+	if original == "" || variant == VariantOriginal || !hashedName.MatchString(path.Base(original)) {
+		return original
+	}
+
+	ext := path.Ext(original)
+	return strings.TrimSuffix(original, ext) + "_" + string(variant) + ext
+}
+
+// putOnce tidak menulis ulang file yang sudah ada karena isinya pasti sama
+// (nama diambil dari hash).
+func (s *service) putOnce(key string, data []byte, contentType string) error {
+	exists, err := s.storage.Exists(key)
+	if err != nil || exists {
+		return err
+	}
+
+	return s.storage.Put(key, data, contentType)
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"footballteam/player"
+	"footballteam/team"
+	"footballteam/user"
+
+	"gorm.io/gorm"
+)
+
+// orphanChecks mencari baris yang menunjuk ke data yang sudah tidak ada.
+// Foreign key baru tidak bisa ditambahkan selama baris seperti ini masih ada,
+// jadi pengecekan ini harus jalan sebelum AutoMigrate. Langkah dilewati jika
+// semua foreign key-nya sudah ada, karena database sudah menjamin tidak ada
+// orphan. Urutannya penting: anak dibersihkan setelah induknya supaya tidak
+// ada orphan baru yang tertinggal.
+var orphanChecks = []struct {
+	table       string
+	where       string
+	parents     []string
+	constraints []string
+}{
+	{"players", "team_id NOT IN (SELECT id FROM teams)", []string{"teams"}, []string{"fk_players_team"}},
+	{"staff", "team_id NOT IN (SELECT id FROM teams)", []string{"teams"}, []string{"fk_staff_team"}},
+	{"matches", "home_team_id NOT IN (SELECT id FROM teams) OR away_team_id NOT IN (SELECT id FROM teams)", []string{"teams"}, []string{"fk_matches_home_team", "fk_matches_away_team"}},
+	{"match_results", "match_id NOT IN (SELECT id FROM matches)", []string{"matches"}, []string{"fk_match_results_match"}},
+	{"goals", "match_result_id NOT IN (SELECT id FROM match_results) OR player_id NOT IN (SELECT id FROM players) OR team_id NOT IN (SELECT id FROM teams)", []string{"match_results", "players", "teams"}, []string{"fk_match_results_goals", "fk_goals_player", "fk_goals_team"}},
+}
+
+// checkOrphans menghentikan migrasi jika ada orphan, dengan daftar tabel dan
+// ID-nya. Data hanya dihapus jika remove bernilai true (CLEAN_ORPHANS=true),
+// supaya tidak ada data yang hilang tanpa disadari.
+func checkOrphans(db *gorm.DB, remove bool) error {
+	migrator := db.Migrator()
+	var report []string
+
+	for _, step := range orphanChecks {
+		if !migrator.HasTable(step.table) {
+			continue
+		}
+
+		missingParent := false
+		for _, parent := range step.parents {
+			if !migrator.HasTable(parent) {
+				missingParent = true
+			}
+		}
+		if missingParent {
+			continue
+		}
+
+		constrained := true
+		for _, name := range step.constraints {
+			if !migrator.HasConstraint(step.table, name) {
+				constrained = false
+			}
+		}
+		if constrained {
+			continue
+		}
+
+		var ids []int
+		if err := db.Table(step.table).Where(step.where).Order("id").Pluck("id", &ids).Error; err != nil {
+			return fmt.Errorf("find orphaned %s: %w", step.table, err)
+		}
+		if len(ids) == 0 {
+			continue
+		}
+
+		if !remove {
+			report = append(report, fmt.Sprintf("%s %v", step.table, ids))
+			continue
+		}
+
+		if err := db.Exec(fmt.Sprintf("DELETE FROM %s WHERE id IN ?", step.table), ids).Error; err != nil {
+			return fmt.Errorf("clean orphaned %s: %w", step.table, err)
+		}
+		log.Printf("⚠️ Removed %d orphaned row(s) from %s: %v", len(ids), step.table, ids)
+	}
+
+	if len(report) > 0 {
+		return fmt.Errorf("orphaned rows found, fix them or set CLEAN_ORPHANS=true to delete them: %s", strings.Join(report, "; "))
+	}
+
+	return nil
+}
+
+// legacyUploadPrefix adalah awalan path logo sebelum ada storage backend.
+// Sekarang kolom logo menyimpan storage key, misalnya "logo/<hash>.png".
+const legacyUploadPrefix = "uploads/"
+
+func migrateLogoKeys(db *gorm.DB) error {
+	result := db.Unscoped().Model(&team.Team{}).
+		Where("logo LIKE ?", legacyUploadPrefix+"%").
+		UpdateColumn("logo", gorm.Expr("SUBSTRING(logo, ?)", len(legacyUploadPrefix)+1))
+	if result.Error != nil {
+		return fmt.Errorf("migrate logo keys: %w", result.Error)
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("ℹ️ Converted %d team logo path(s) to storage keys", result.RowsAffected)
+	}
+
+	return nil
+}
+
+// backfillTeamSlugs mengisi name_key dan slug untuk tim yang dibuat sebelum
+// kolom itu ada. Harus jalan sebelum AutoMigrate karena unique index slug
+// tidak bisa dibuat selama semua baris masih bernilai kosong. Tim dengan nama
+// yang sama (beda huruf besar/kecil atau aksen) tetap dipertahankan dan hanya
+// dicatat di log; slug-nya diberi akhiran -2, -3, dan seterusnya.
+func backfillTeamSlugs(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable(&team.Team{}) || migrator.HasColumn(&team.Team{}, "Slug") {
+		return nil
+	}
+
+	for _, field := range []string{"NameKey", "Slug"} {
+		if migrator.HasColumn(&team.Team{}, field) {
+			continue
+		}
+		if err := migrator.AddColumn(&team.Team{}, field); err != nil {
+			return fmt.Errorf("add teams.%s: %w", field, err)
+		}
+	}
+
+	var teams []team.Team
+	if err := db.Unscoped().Select("id", "name").Order("id").Find(&teams).Error; err != nil {
+		return fmt.Errorf("load teams: %w", err)
+	}
+
+	usedSlugs := make(map[string]bool)
+	firstByKey := make(map[string]int)
+	for _, t := range teams {
+		nameKey := team.NameKey(t.Name)
+		if firstID, ok := firstByKey[nameKey]; ok {
+			log.Printf("⚠️ Team %d has the same name as team %d: %q", t.ID, firstID, t.Name)
+		} else {
+			firstByKey[nameKey] = t.ID
+		}
+
+		base := team.Slugify(t.Name)
+		slug := base
+		for i := 2; usedSlugs[slug]; i++ {
+			slug = fmt.Sprintf("%s-%d", base, i)
+		}
+		usedSlugs[slug] = true
+
+		err := db.Unscoped().Model(&team.Team{}).Where("id = ?", t.ID).
+			UpdateColumns(map[string]interface{}{"name_key": nameKey, "slug": slug}).Error
+		if err != nil {
+			return fmt.Errorf("backfill slug for team %d: %w", t.ID, err)
+		}
+	}
+
+	if len(teams) > 0 {
+		log.Printf("ℹ️ Generated slugs for %d team(s)", len(teams))
+	}
+
+	return nil
+}
+
+// addUserRoleColumn menambahkan kolom role dengan nilai kosong untuk user yang
+// dibuat sebelum ada role. Harus jalan sebelum AutoMigrate; kalau tidak,
+// kolom langsung terisi default viewer dan user lama tidak bisa dibedakan
+// dari user yang memang viewer. AutoMigrate lalu mengganti default-nya.
+func addUserRoleColumn(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable(&user.User{}) || migrator.HasColumn(&user.User{}, "Role") {
+		return nil
+	}
+
+	if err := db.Exec("ALTER TABLE users ADD COLUMN role varchar(20) NOT NULL DEFAULT ''").Error; err != nil {
+		return fmt.Errorf("add users.role: %w", err)
+	}
+	return nil
+}
+
+// backfillUserRoles menjadikan admin user yang role-nya masih kosong, karena
+// sebelum ada role setiap user yang bisa login adalah admin. Role yang sudah
+// diisi tidak diubah, jadi aman dijalankan di setiap start.
+func backfillUserRoles(db *gorm.DB) error {
+	err := db.Model(&user.User{}).
+		Where("role = '' OR role IS NULL").
+		Update("role", user.RoleAdmin).Error
+	if err != nil {
+		return fmt.Errorf("backfill users.role: %w", err)
+	}
+	return nil
+}
+
+// backfillTeamVersions membuat versi awal untuk tim yang belum punya history,
+// berlaku sejak tim dibuat. Perubahan nama atau logo sebelum fitur ini ada
+// tidak tercatat, jadi pertandingan lama memakai atribut tim saat migrasi.
+func backfillTeamVersions(db *gorm.DB) error {
+	var teams []team.Team
+	err := db.Unscoped().
+		Where("NOT EXISTS (SELECT 1 FROM team_versions WHERE team_versions.team_id = teams.id)").
+		Order("id").
+		Find(&teams).Error
+	if err != nil {
+		return fmt.Errorf("load teams without history: %w", err)
+	}
+	if len(teams) == 0 {
+		return nil
+	}
+
+	versions := make([]team.TeamVersion, 0, len(teams))
+	for _, t := range teams {
+		versions = append(versions, t.Snapshot(t.CreatedAt))
+	}
+	if err := db.CreateInBatches(&versions, 100).Error; err != nil {
+		return fmt.Errorf("backfill team versions: %w", err)
+	}
+
+	log.Printf("ℹ️ Created initial history for %d team(s)", len(teams))
+	return nil
+}
+
+// migratePlayerPositions mengubah posisi teks bebas ("Penyerang", "Penjaga
+// Gawang", dan sebagainya) menjadi kode posisi. Nilai yang tidak dikenali
+// dibiarkan dan dicatat di log supaya bisa diperbaiki manual; API tetap
+// menampilkannya apa adanya.
+func migratePlayerPositions(db *gorm.DB) error {
+	var values []string
+	if err := db.Unscoped().Model(&player.Player{}).Distinct().Pluck("position", &values).Error; err != nil {
+		return fmt.Errorf("load player positions: %w", err)
+	}
+
+	var converted int64
+	for _, value := range values {
+		code, ok := player.NormalizePosition(value)
+		if !ok {
+			log.Printf("⚠️ Unknown player position %q, left unchanged", value)
+			continue
+		}
+		if code == value {
+			continue
+		}
+
+		result := db.Unscoped().Model(&player.Player{}).
+			Where("position = ?", value).
+			UpdateColumn("position", code)
+		if result.Error != nil {
+			return fmt.Errorf("migrate player position %q: %w", value, result.Error)
+		}
+		converted += result.RowsAffected
+	}
+
+	if converted > 0 {
+		log.Printf("ℹ️ Converted %d player position(s) to position codes", converted)
+	}
+
+	return nil
+}
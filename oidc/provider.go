@@ -0,0 +1,188 @@
+package oidc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrProviderUnavailable = errors.New("identity provider unavailable")
+
+// jwksRefreshInterval membatasi pengambilan ulang JWKS saat kid tidak dikenal
+const jwksRefreshInterval = time.Minute
+
+type discoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// provider menyimpan hasil discovery dan JWKS. Discovery dilakukan saat
+// pertama dibutuhkan supaya API tetap bisa start walau IdP sedang down.
+type provider struct {
+	issuerURL  string
+	httpClient *http.Client
+
+	mu          sync.Mutex
+	document    *discoveryDocument
+	keys        map[string]interface{}
+	keysFetched time.Time
+}
+
+func newProvider(issuerURL string, httpClient *http.Client) *provider {
+	return &provider{
+		issuerURL:  strings.TrimSuffix(issuerURL, "/"),
+		httpClient: httpClient,
+	}
+}
+
+func (p *provider) discover() (discoveryDocument, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.document != nil {
+		return *p.document, nil
+	}
+
+	var document discoveryDocument
+	if err := p.getJSON(p.issuerURL+"/.well-known/openid-configuration", &document); err != nil {
+		return document, err
+	}
+
+	// OIDC Discovery 4.3: issuer harus sama persis dengan URL yang dipakai
+	if strings.TrimSuffix(document.Issuer, "/") != p.issuerURL {
+		return document, fmt.Errorf("%w: issuer mismatch %q", ErrProviderUnavailable, document.Issuer)
+	}
+	if document.AuthorizationEndpoint == "" || document.TokenEndpoint == "" || document.JWKSURI == "" {
+		return document, fmt.Errorf("%w: incomplete discovery document", ErrProviderUnavailable)
+	}
+
+	p.document = &document
+	return document, nil
+}
+
+// key mencari public key berdasarkan kid. JWKS diambil ulang jika kid belum
+// dikenal, misalnya setelah IdP merotasi kuncinya.
+func (p *provider) key(kid string) (interface{}, error) {
+	document, err := p.discover()
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+	if time.Since(p.keysFetched) < jwksRefreshInterval {
+		return nil, ErrInvalidIDToken
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := p.getJSON(document.JWKSURI, &set); err != nil {
+		return nil, err
+	}
+
+	keys := map[string]interface{}{}
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		publicKey, err := parseJWK(k)
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = publicKey
+	}
+	p.keys = keys
+	p.keysFetched = time.Now()
+
+	key, ok := p.keys[kid]
+	if !ok {
+		return nil, ErrInvalidIDToken
+	}
+	return key, nil
+}
+
+func (p *provider) getJSON(url string, target interface{}) error {
+	resp, err := p.httpClient.Get(url)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrProviderUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s returned %d", ErrProviderUnavailable, url, resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+		return fmt.Errorf("%w: %v", ErrProviderUnavailable, err)
+	}
+	return nil
+}
+
+func parseJWK(k jsonWebKey) (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		publicKey := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(publicKey.X, publicKey.Y) {
+			return nil, errors.New("EC point is not on curve")
+		}
+		return publicKey, nil
+	}
+
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
@@ -0,0 +1,259 @@
+package oidc
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"footballteam/helper"
+
+	"github.com/golang-jwt/jwt"
+)
+
+var (
+	ErrInvalidState   = errors.New("invalid or expired login state")
+	ErrInvalidIDToken = errors.New("invalid ID token")
+	ErrCodeExchange   = errors.New("authorization code exchange failed")
+)
+
+const (
+	DefaultStateTTL    = 10 * time.Minute
+	DefaultHTTPTimeout = 10 * time.Second
+
+	// clockSkew memberi toleransi selisih jam antara API dan IdP
+	clockSkew = time.Minute
+)
+
+// Algoritma yang diterima untuk ID token. HS256 sengaja tidak diterima karena
+// kita hanya memverifikasi dengan public key dari JWKS.
+var allowedAlgorithms = map[string]bool{
+	"RS256": true, "RS384": true, "RS512": true,
+	"ES256": true, "ES384": true, "ES512": true,
+}
+
+type Config struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	StateTTL     time.Duration
+	// HTTPClient bisa diganti, misalnya untuk mock provider di lokal
+	HTTPClient *http.Client
+}
+
+// Claims adalah identitas user hasil ID token yang sudah terverifikasi.
+type Claims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+type Service interface {
+	AuthCodeURL() (string, string, error)
+	Exchange(state string, code string) (Claims, error)
+	StateTTL() time.Duration
+}
+
+type service struct {
+	config   Config
+	provider *provider
+	states   *stateStore
+}
+
+func NewService(config Config) *service {
+	if config.StateTTL <= 0 {
+		config.StateTTL = DefaultStateTTL
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: DefaultHTTPTimeout}
+	}
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "email", "profile"}
+	}
+
+	return &service{
+		config:   config,
+		provider: newProvider(config.IssuerURL, config.HTTPClient),
+		states:   newStateStore(config.StateTTL),
+	}
+}
+
+// AuthCodeURL membuat URL authorization dengan PKCE (S256) dan nonce, lalu
+// mengembalikan URL tersebut beserta state-nya.
+func (s *service) AuthCodeURL() (string, string, error) {
+	document, err := s.provider.discover()
+	if err != nil {
+		return "", "", err
+	}
+
+	state, err := helper.GenerateRandomToken(32)
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := helper.GenerateRandomToken(32)
+	if err != nil {
+		return "", "", err
+	}
+	codeVerifier, err := helper.GenerateRandomToken(32)
+	if err != nil {
+		return "", "", err
+	}
+
+	s.states.put(state, pendingLogin{codeVerifier: codeVerifier, nonce: nonce})
+
+	challenge := sha256.Sum256([]byte(codeVerifier))
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", s.config.ClientID)
+	params.Set("redirect_uri", s.config.RedirectURL)
+	params.Set("scope", strings.Join(s.config.Scopes, " "))
+	params.Set("state", state)
+	params.Set("nonce", nonce)
+	params.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	params.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(document.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return document.AuthorizationEndpoint + separator + params.Encode(), state, nil
+}
+
+func (s *service) StateTTL() time.Duration {
+	return s.config.StateTTL
+}
+
+// Exchange menukar authorization code ke token endpoint dan memverifikasi
+// ID token yang dikembalikan.
+func (s *service) Exchange(state string, code string) (Claims, error) {
+	login, ok := s.states.take(state)
+	if !ok {
+		return Claims{}, ErrInvalidState
+	}
+
+	document, err := s.provider.discover()
+	if err != nil {
+		return Claims{}, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", s.config.RedirectURL)
+	form.Set("client_id", s.config.ClientID)
+	form.Set("code_verifier", login.codeVerifier)
+
+	req, err := http.NewRequest(http.MethodPost, document.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Claims{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if s.config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(s.config.ClientID), url.QueryEscape(s.config.ClientSecret))
+	}
+
+	resp, err := s.config.HTTPClient.Do(req)
+	if err != nil {
+		return Claims{}, fmt.Errorf("%w: %v", ErrProviderUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	var tokenResponse struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResponse); err != nil {
+		return Claims{}, fmt.Errorf("%w: %v", ErrCodeExchange, err)
+	}
+	if resp.StatusCode != http.StatusOK || tokenResponse.Error != "" {
+		return Claims{}, fmt.Errorf("%w: %s %s", ErrCodeExchange, tokenResponse.Error, tokenResponse.ErrorDescription)
+	}
+	if tokenResponse.IDToken == "" {
+		return Claims{}, fmt.Errorf("%w: no id_token in response", ErrCodeExchange)
+	}
+
+	return s.verifyIDToken(tokenResponse.IDToken, document.Issuer, login.nonce)
+}
+
+func (s *service) verifyIDToken(rawToken string, issuer string, nonce string) (Claims, error) {
+	parser := jwt.Parser{SkipClaimsValidation: true}
+	token, err := parser.Parse(rawToken, func(token *jwt.Token) (interface{}, error) {
+		if !allowedAlgorithms[token.Method.Alg()] {
+			return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+		}
+
+		kid, _ := token.Header["kid"].(string)
+		return s.provider.key(kid)
+	})
+	if err != nil || !token.Valid {
+		return Claims{}, ErrInvalidIDToken
+	}
+
+	claim, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return Claims{}, ErrInvalidIDToken
+	}
+
+	now := time.Now()
+	if !claim.VerifyIssuer(issuer, true) {
+		return Claims{}, fmt.Errorf("%w: issuer mismatch", ErrInvalidIDToken)
+	}
+	if !hasAudience(claim["aud"], s.config.ClientID) {
+		return Claims{}, fmt.Errorf("%w: audience mismatch", ErrInvalidIDToken)
+	}
+	if !claim.VerifyExpiresAt(now.Add(-clockSkew).Unix(), true) {
+		return Claims{}, fmt.Errorf("%w: token expired", ErrInvalidIDToken)
+	}
+	if !claim.VerifyIssuedAt(now.Add(clockSkew).Unix(), false) {
+		return Claims{}, fmt.Errorf("%w: token issued in the future", ErrInvalidIDToken)
+	}
+
+	tokenNonce, _ := claim["nonce"].(string)
+	if subtle.ConstantTimeCompare([]byte(tokenNonce), []byte(nonce)) != 1 {
+		return Claims{}, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+
+	subject, _ := claim["sub"].(string)
+	if subject == "" {
+		return Claims{}, fmt.Errorf("%w: missing subject", ErrInvalidIDToken)
+	}
+
+	claims := Claims{Subject: subject}
+	claims.Email, _ = claim["email"].(string)
+	claims.Name, _ = claim["name"].(string)
+	switch verified := claim["email_verified"].(type) {
+	case bool:
+		claims.EmailVerified = verified
+	case string:
+		// Beberapa IdP mengirim "true" sebagai string
+		claims.EmailVerified = verified == "true"
+	}
+
+	return claims, nil
+}
+
+func hasAudience(aud interface{}, clientID string) bool {
+	switch value := aud.(type) {
+	case string:
+		return value == clientID
+	case []interface{}:
+		for _, a := range value {
+			if a == clientID {
+				return true
+			}
+		}
+	}
+	return false
+}
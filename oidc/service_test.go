@@ -0,0 +1,262 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+const (
+	testClientID = "footballteam"
+	testKeyID    = "test-key"
+	testCode     = "test-code"
+)
+
+// mockProvider adalah IdP minimal: discovery, JWKS, dan token endpoint yang
+// menandatangani ID token dengan RSA key milik test.
+type mockProvider struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu            sync.Mutex
+	claims        jwt.MapClaims
+	codeChallenge string
+	codeVerifier  string
+}
+
+func newMockProvider(t *testing.T) *mockProvider {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &mockProvider{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(discoveryDocument{
+			Issuer:                m.server.URL,
+			AuthorizationEndpoint: m.server.URL + "/authorize",
+			TokenEndpoint:         m.server.URL + "/token",
+			JWKSURI:               m.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string][]jsonWebKey{"keys": {{
+			Kty: "RSA",
+			Kid: testKeyID,
+			Use: "sig",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", m.token)
+
+	m.server = httptest.NewServer(mux)
+	t.Cleanup(m.server.Close)
+	return m
+}
+
+// token hanya menerbitkan ID token jika code dan PKCE verifier cocok.
+func (m *mockProvider) token(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := r.ParseForm(); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	m.codeVerifier = r.PostForm.Get("code_verifier")
+
+	sum := sha256.Sum256([]byte(m.codeVerifier))
+	if r.PostForm.Get("grant_type") != "authorization_code" || r.PostForm.Get("code") != testCode ||
+		base64.RawURLEncoding.EncodeToString(sum[:]) != m.codeChallenge {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, m.claims)
+	token.Header["kid"] = testKeyID
+	signed, err := token.SignedString(m.key)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"id_token": signed, "token_type": "Bearer"})
+}
+
+// login menjalankan flow lengkap: AuthCodeURL, lalu Exchange dengan ID token
+// yang claim-nya bisa diubah lewat mutate.
+func (m *mockProvider) login(t *testing.T, s *service, mutate func(jwt.MapClaims)) (Claims, error) {
+	t.Helper()
+
+	authURL, state, err := s.AuthCodeURL()
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := parsed.Query()
+	if query.Get("code_challenge_method") != "S256" {
+		t.Fatalf("code_challenge_method = %q, want S256", query.Get("code_challenge_method"))
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":            m.server.URL,
+		"aud":            testClientID,
+		"sub":            "subject-1",
+		"email":          "budi@example.com",
+		"email_verified": true,
+		"name":           "Budi",
+		"nonce":          query.Get("nonce"),
+		"iat":            now.Unix(),
+		"exp":            now.Add(5 * time.Minute).Unix(),
+	}
+	if mutate != nil {
+		mutate(claims)
+	}
+
+	m.mu.Lock()
+	m.claims = claims
+	m.codeChallenge = query.Get("code_challenge")
+	m.mu.Unlock()
+
+	return s.Exchange(state, testCode)
+}
+
+func newTestService(m *mockProvider) *service {
+	return NewService(Config{
+		IssuerURL:   m.server.URL,
+		ClientID:    testClientID,
+		RedirectURL: "http://localhost/callback",
+		HTTPClient:  m.server.Client(),
+	})
+}
+
+func TestExchange(t *testing.T) {
+	m := newMockProvider(t)
+	s := newTestService(m)
+
+	claims, err := m.login(t, s, nil)
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+
+	want := Claims{Subject: "subject-1", Email: "budi@example.com", EmailVerified: true, Name: "Budi"}
+	if claims != want {
+		t.Errorf("claims = %+v, want %+v", claims, want)
+	}
+	if m.codeVerifier == "" {
+		t.Error("code_verifier was not sent to the token endpoint")
+	}
+}
+
+func TestExchangeUnverifiedEmail(t *testing.T) {
+	m := newMockProvider(t)
+	s := newTestService(m)
+
+	// Penolakan terjadi di user.LoginWithOIDC; di sini cukup dipastikan
+	// claim-nya diteruskan apa adanya
+	for _, verified := range []interface{}{false, "false"} {
+		claims, err := m.login(t, s, func(c jwt.MapClaims) { c["email_verified"] = verified })
+		if err != nil {
+			t.Fatalf("Exchange: %v", err)
+		}
+		if claims.EmailVerified {
+			t.Errorf("email_verified %v: EmailVerified = true", verified)
+		}
+	}
+}
+
+func TestExchangeRejectsInvalidIDToken(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(jwt.MapClaims)
+	}{
+		{"nonce mismatch", func(c jwt.MapClaims) { c["nonce"] = "other-nonce" }},
+		{"missing nonce", func(c jwt.MapClaims) { delete(c, "nonce") }},
+		{"wrong issuer", func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }},
+		{"wrong audience", func(c jwt.MapClaims) { c["aud"] = "other-client" }},
+		{"audience list without client", func(c jwt.MapClaims) { c["aud"] = []string{"a", "b"} }},
+		{"expired", func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-2 * clockSkew).Unix() }},
+		{"missing exp", func(c jwt.MapClaims) { delete(c, "exp") }},
+		{"issued in the future", func(c jwt.MapClaims) { c["iat"] = time.Now().Add(2 * clockSkew).Unix() }},
+		{"missing subject", func(c jwt.MapClaims) { delete(c, "sub") }},
+	}
+
+	m := newMockProvider(t)
+	s := newTestService(m)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := m.login(t, s, tt.mutate)
+			if !errors.Is(err, ErrInvalidIDToken) {
+				t.Errorf("err = %v, want ErrInvalidIDToken", err)
+			}
+		})
+	}
+}
+
+func TestExchangeAcceptsAudienceList(t *testing.T) {
+	m := newMockProvider(t)
+	s := newTestService(m)
+
+	_, err := m.login(t, s, func(c jwt.MapClaims) { c["aud"] = []string{"other-client", testClientID} })
+	if err != nil {
+		t.Errorf("Exchange: %v", err)
+	}
+}
+
+func TestExchangeStateIsSingleUse(t *testing.T) {
+	m := newMockProvider(t)
+	s := newTestService(m)
+
+	_, state, err := s.AuthCodeURL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Exchange(state, "wrong-code")
+
+	if _, err := s.Exchange(state, testCode); !errors.Is(err, ErrInvalidState) {
+		t.Errorf("second Exchange err = %v, want ErrInvalidState", err)
+	}
+	if _, err := s.Exchange("unknown-state", testCode); !errors.Is(err, ErrInvalidState) {
+		t.Errorf("unknown state err = %v, want ErrInvalidState", err)
+	}
+}
+
+func TestExchangeWrongVerifierFails(t *testing.T) {
+	m := newMockProvider(t)
+	s := newTestService(m)
+
+	_, err := m.login(t, s, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Challenge dari login lain tidak cocok dengan verifier login ini
+	_, state, err := s.AuthCodeURL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Exchange(state, testCode); !errors.Is(err, ErrCodeExchange) {
+		t.Errorf("err = %v, want ErrCodeExchange", err)
+	}
+}
@@ -0,0 +1,58 @@
+package oidc
+
+import (
+	"sync"
+	"time"
+)
+
+// pendingLogin disimpan antara redirect ke IdP dan callback.
+type pendingLogin struct {
+	codeVerifier string
+	nonce        string
+	expiresAt    time.Time
+}
+
+// stateStore menyimpan login yang sedang berjalan di memori. Karena tidak
+// dibagi antar instance, callback harus kembali ke instance yang sama
+// (sticky session) jika API dijalankan lebih dari satu.
+type stateStore struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	pending map[string]pendingLogin
+}
+
+func newStateStore(ttl time.Duration) *stateStore {
+	return &stateStore{ttl: ttl, pending: map[string]pendingLogin{}}
+}
+
+func (s *stateStore) put(state string, login pendingLogin) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for key, p := range s.pending {
+		if now.After(p.expiresAt) {
+			delete(s.pending, key)
+		}
+	}
+
+	login.expiresAt = now.Add(s.ttl)
+	s.pending[state] = login
+}
+
+// take mengambil dan menghapus state sehingga setiap state hanya bisa dipakai sekali.
+func (s *stateStore) take(state string) (pendingLogin, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	login, ok := s.pending[state]
+	if !ok {
+		return login, false
+	}
+	delete(s.pending, state)
+
+	if time.Now().After(login.expiresAt) {
+		return login, false
+	}
+	return login, true
+}
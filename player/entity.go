@@ -3,18 +3,52 @@ package player
 import (
 	"time"
 
+	"footballteam/team"
+
 	"gorm.io/gorm"
 )
 
 type Player struct {
-	ID        int       `gorm:"primaryKey;autoIncrement"`
-	Name      string    `gorm:"type:varchar(100);not null"`
-	Height    float64   `gorm:"not null"`
-	Weight    float64   `gorm:"not null"`
-	Position  string    `gorm:"type:varchar(50);not null"` // Penyerang, Gelandang, Bertahan, Penjaga Gawang
-	Number    int       `gorm:"not null"`
-	TeamID    int       `gorm:"not null;index"`
+	ID        int        `gorm:"primaryKey;autoIncrement"`
+	Name      string     `gorm:"type:varchar(100);not null"`
+	Height    float64    `gorm:"not null"`
+	Weight    float64    `gorm:"not null"`
+	Position  string     `gorm:"type:varchar(50);not null"` // kode posisi, lihat position.go
+	Number    int        `gorm:"not null"`
+	BirthDate *time.Time `gorm:"type:date"`
+	TeamID    int        `gorm:"not null;index"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	DeletedAt gorm.DeletedAt `gorm:"index"`
-}
\ No newline at end of file
+
+	// Relasi, dipakai untuk foreign key. Tim yang masih punya pemain tidak
+	// bisa dihapus permanen.
+	Team *team.Team `gorm:"constraint:OnUpdate:CASCADE,OnDelete:RESTRICT"`
+}
+
+// Jenis transfer pemain.
+const (
+	TransferPermanent  = "permanent"
+	TransferLoan       = "loan"
+	TransferLoanReturn = "loan_return"
+	TransferFree       = "free"
+)
+
+// Transfer mencatat perpindahan pemain antar tim. Baris ini adalah history,
+// jadi tidak bisa diubah atau dihapus lewat API; tim yang masih tercatat di
+// transfer tidak bisa dihapus permanen.
+type Transfer struct {
+	ID         int       `gorm:"primaryKey;autoIncrement"`
+	PlayerID   int       `gorm:"not null;index"`
+	FromTeamID int       `gorm:"not null;index"`
+	ToTeamID   int       `gorm:"not null;index"`
+	Date       time.Time `gorm:"type:date;not null"`
+	Type       string    `gorm:"type:varchar(20);not null"`
+	Fee        *float64  `gorm:"type:decimal(15,2)"` // nil jika tidak diumumkan
+	Number     int       `gorm:"not null"`           // nomor punggung di tim tujuan
+	CreatedAt  time.Time
+
+	Player   *Player    `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	FromTeam *team.Team `gorm:"constraint:OnUpdate:CASCADE,OnDelete:RESTRICT"`
+	ToTeam   *team.Team `gorm:"constraint:OnUpdate:CASCADE,OnDelete:RESTRICT"`
+}
@@ -1,31 +1,120 @@
 package player
 
+import (
+	"time"
+
+	"footballteam/team"
+)
+
 type PlayerFormatter struct {
-	ID       int     `json:"id"`
-	Name     string  `json:"name"`
-	Height   float64 `json:"height"`
-	Weight   float64 `json:"weight"`
-	Position string  `json:"position"`
-	Number   int     `json:"number"`
-	TeamID   int     `json:"team_id"`
+	ID            int     `json:"id"`
+	Name          string  `json:"name"`
+	Height        float64 `json:"height"`
+	Weight        float64 `json:"weight"`
+	Position      string  `json:"position"`
+	PositionLabel string  `json:"position_label"`
+	PositionGroup string  `json:"position_group"`
+	Number        int     `json:"number"`
+	BirthDate     *string `json:"birth_date"`
+	Age           *int    `json:"age"`
+	TeamID        int     `json:"team_id"`
 }
 
-func FormatPlayer(player Player) PlayerFormatter {
-	return PlayerFormatter{
-		ID:       player.ID,
-		Name:     player.Name,
-		Height:   player.Height,
-		Weight:   player.Weight,
-		Position: player.Position,
-		Number:   player.Number,
-		TeamID:   player.TeamID,
+// FormatPlayer menampilkan label posisi dalam bahasa lang (LangID atau
+// LangEN).
+func FormatPlayer(player Player, lang string) PlayerFormatter {
+	formatter := PlayerFormatter{
+		ID:            player.ID,
+		Name:          player.Name,
+		Height:        player.Height,
+		Weight:        player.Weight,
+		Position:      player.Position,
+		PositionLabel: PositionLabel(player.Position, lang),
+		PositionGroup: PositionGroup(player.Position),
+		Number:        player.Number,
+		TeamID:        player.TeamID,
 	}
+	if player.BirthDate != nil {
+		birthDate := player.BirthDate.Format(dateLayout)
+		age := Age(*player.BirthDate, time.Now())
+		formatter.BirthDate = &birthDate
+		formatter.Age = &age
+	}
+	return formatter
 }
 
-func FormatPlayers(players []Player) []PlayerFormatter {
+// Age menghitung umur dalam tahun penuh pada tanggal today.
+func Age(birthDate time.Time, today time.Time) int {
+	age := today.Year() - birthDate.Year()
+	if today.Month() < birthDate.Month() || (today.Month() == birthDate.Month() && today.Day() < birthDate.Day()) {
+		age--
+	}
+	return age
+}
+
+func FormatPlayers(players []Player, lang string) []PlayerFormatter {
 	formatted := []PlayerFormatter{}
 	for _, p := range players {
-		formatted = append(formatted, FormatPlayer(p))
+		formatted = append(formatted, FormatPlayer(p, lang))
+	}
+	return formatted
+}
+
+type TrashedPlayerFormatter struct {
+	PlayerFormatter
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+func FormatTrashedPlayers(players []Player, lang string) []TrashedPlayerFormatter {
+	formatted := []TrashedPlayerFormatter{}
+	for _, p := range players {
+		formatted = append(formatted, TrashedPlayerFormatter{FormatPlayer(p, lang), p.DeletedAt.Time})
+	}
+	return formatted
+}
+
+// TransferTeamFormatter memakai nama tim yang berlaku pada tanggal transfer.
+type TransferTeamFormatter struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type TransferFormatter struct {
+	ID       int                   `json:"id"`
+	PlayerID int                   `json:"player_id"`
+	FromTeam TransferTeamFormatter `json:"from_team"`
+	ToTeam   TransferTeamFormatter `json:"to_team"`
+	Date     string                `json:"date"`
+	Type     string                `json:"type"`
+	Fee      *float64              `json:"fee"`
+	Number   int                   `json:"number"`
+}
+
+func FormatTransfer(transfer Transfer) TransferFormatter {
+	return TransferFormatter{
+		ID:       transfer.ID,
+		PlayerID: transfer.PlayerID,
+		FromTeam: formatTransferTeam(transfer.FromTeamID, transfer.FromTeam, transfer.Date),
+		ToTeam:   formatTransferTeam(transfer.ToTeamID, transfer.ToTeam, transfer.Date),
+		Date:     transfer.Date.Format(dateLayout),
+		Type:     transfer.Type,
+		Fee:      transfer.Fee,
+		Number:   transfer.Number,
+	}
+}
+
+func formatTransferTeam(id int, t *team.Team, date time.Time) TransferTeamFormatter {
+	formatter := TransferTeamFormatter{ID: id}
+	if t != nil {
+		formatter.Name = t.AsOf(date).Name
+	}
+	return formatter
+}
+
+func FormatTransfers(transfers []Transfer) []TransferFormatter {
+	formatted := []TransferFormatter{}
+	for _, t := range transfers {
+		formatted = append(formatted, FormatTransfer(t))
 	}
 	return formatted
 }
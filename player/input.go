@@ -1,19 +1,90 @@
 package player
 
+import (
+	"time"
+
+	"footballteam/helper"
+	"footballteam/user"
+)
+
+// BirthDate memakai format YYYY-MM-DD.
 type CreatePlayerInput struct {
-	Name     string  `json:"name" binding:"required"`
-	Height   float64 `json:"height" binding:"required"`
-	Weight   float64 `json:"weight" binding:"required"`
-	Position string  `json:"position" binding:"required"`
-	Number   int     `json:"number" binding:"required"`
-	TeamID   int     `json:"team_id" binding:"required"`
+	Name      string    `json:"name" binding:"required"`
+	Height    float64   `json:"height" binding:"required"`
+	Weight    float64   `json:"weight" binding:"required"`
+	Position  string    `json:"position" binding:"required,position"`
+	Number    int       `json:"number" binding:"required"`
+	BirthDate string    `json:"birth_date" binding:"omitempty,datetime=2006-01-02"`
+	TeamID    int       `json:"team_id" binding:"required"`
+	User      user.User `json:"-"`
 }
 
 type UpdatePlayerInput struct {
-	Name     string  `json:"name"`
-	Height   float64 `json:"height"`
-	Weight   float64 `json:"weight"`
-	Position string  `json:"position"`
-	Number   int     `json:"number"`
-	TeamID   int     `json:"team_id"`
+	Name      string    `json:"name"`
+	Height    float64   `json:"height"`
+	Weight    float64   `json:"weight"`
+	Position  string    `json:"position" binding:"omitempty,position"`
+	Number    int       `json:"number"`
+	BirthDate string    `json:"birth_date" binding:"omitempty,datetime=2006-01-02"`
+	TeamID    int       `json:"team_id"`
+	User      user.User `json:"-"`
+}
+
+// ListPlayersInput dibind dari query string GET /players. Rentang bersifat
+// inklusif; filter umur hanya mencakup pemain yang punya birth_date. Sort
+// diawali "-" untuk urutan menurun, misalnya "-height". Position berupa kode
+// kelompok (GK, DF, MF, FW) mencakup semua peran detail di kelompok itu.
+type ListPlayersInput struct {
+	helper.PageInput
+	Query      string  `form:"q"`
+	TeamID     int     `form:"team_id" binding:"omitempty,min=1"`
+	Position   string  `form:"position" binding:"omitempty,position"`
+	NumberFrom int     `form:"number_from" binding:"omitempty,min=1"`
+	NumberTo   int     `form:"number_to" binding:"omitempty,min=1,gtefield=NumberFrom"`
+	HeightFrom float64 `form:"height_from" binding:"omitempty,gt=0"`
+	HeightTo   float64 `form:"height_to" binding:"omitempty,gt=0,gtefield=HeightFrom"`
+	WeightFrom float64 `form:"weight_from" binding:"omitempty,gt=0"`
+	WeightTo   float64 `form:"weight_to" binding:"omitempty,gt=0,gtefield=WeightFrom"`
+	AgeFrom    int     `form:"age_from" binding:"omitempty,min=1"`
+	AgeTo      int     `form:"age_to" binding:"omitempty,min=1,gtefield=AgeFrom"`
+	Sort       string  `form:"sort" binding:"omitempty,oneof=name -name number -number height -height weight -weight birth_date -birth_date created_at -created_at"`
+}
+
+// TransferPlayerInput dipakai POST /players/:id/transfers. Date (YYYY-MM-DD)
+// default hari ini; Number default nomor punggung saat ini.
+type TransferPlayerInput struct {
+	ToTeamID int       `json:"to_team_id" binding:"required,min=1"`
+	Date     string    `json:"date" binding:"omitempty,datetime=2006-01-02"`
+	Type     string    `json:"type" binding:"required,oneof=permanent loan loan_return free"`
+	Fee      *float64  `json:"fee" binding:"omitempty,min=0"`
+	Number   int       `json:"number" binding:"omitempty,min=1"`
+	User     user.User `json:"-"`
+}
+
+// birthDateBounds mengubah filter umur menjadi rentang birth_date, konsisten
+// dengan Age: umur minimal N berarti lahir paling lambat N tahun sebelum
+// today, umur maksimal N berarti lahir setelah N+1 tahun sebelum today. Nilai
+// nil berarti batas itu tidak dipakai.
+func (input ListPlayersInput) birthDateBounds(today time.Time) (bornOnOrBefore *time.Time, bornAfter *time.Time) {
+	if input.AgeFrom != 0 {
+		date := yearsBefore(today, input.AgeFrom)
+		bornOnOrBefore = &date
+	}
+	if input.AgeTo != 0 {
+		date := yearsBefore(today, input.AgeTo+1)
+		bornAfter = &date
+	}
+	return bornOnOrBefore, bornAfter
+}
+
+// yearsBefore mundur n tahun tanpa melompat ke bulan berikutnya: 29 Februari
+// menjadi 28 Februari di tahun yang bukan kabisat (AddDate akan menghasilkan
+// 1 Maret).
+func yearsBefore(date time.Time, n int) time.Time {
+	year, month, day := date.Date()
+	year -= n
+	if lastDay := time.Date(year, month+1, 0, 0, 0, 0, 0, date.Location()).Day(); day > lastDay {
+		day = lastDay
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, date.Location())
 }
@@ -0,0 +1,96 @@
+package player
+
+import (
+	"testing"
+	"time"
+)
+
+func date(value string) time.Time {
+	d, err := time.ParseInLocation(dateLayout, value, time.Local)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func formatBound(d *time.Time) string {
+	if d == nil {
+		return "-"
+	}
+	return d.Format(dateLayout)
+}
+
+func TestBirthDateBounds(t *testing.T) {
+	tests := []struct {
+		name           string
+		today          string
+		ageFrom, ageTo int
+		bornOnOrBefore string
+		bornAfter      string
+	}{
+		{"no filter", "2025-06-15", 0, 0, "-", "-"},
+		{"age from", "2025-06-15", 18, 0, "2007-06-15", "-"},
+		{"age to", "2025-06-15", 0, 21, "-", "2003-06-15"},
+		{"single age", "2025-06-15", 25, 25, "2000-06-15", "1999-06-15"},
+		{"leap day today, non-leap target", "2024-02-29", 18, 0, "2006-02-28", "-"},
+		{"leap day today, leap target", "2024-02-29", 20, 0, "2004-02-29", "-"},
+		{"leap day today, age to", "2024-02-29", 0, 17, "-", "2006-02-28"},
+		{"new year's day", "2025-01-01", 30, 30, "1995-01-01", "1994-01-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := ListPlayersInput{AgeFrom: tt.ageFrom, AgeTo: tt.ageTo}
+			onOrBefore, after := input.birthDateBounds(date(tt.today))
+
+			if got := formatBound(onOrBefore); got != tt.bornOnOrBefore {
+				t.Errorf("bornOnOrBefore = %s, want %s", got, tt.bornOnOrBefore)
+			}
+			if got := formatBound(after); got != tt.bornAfter {
+				t.Errorf("bornAfter = %s, want %s", got, tt.bornAfter)
+			}
+		})
+	}
+}
+
+// Filter umur harus memilih pemain yang sama dengan umur yang ditampilkan
+// oleh Age, termasuk di sekitar ulang tahun dan 29 Februari.
+func TestBirthDateBoundsMatchAge(t *testing.T) {
+	todays := []string{"2024-02-28", "2024-02-29", "2024-03-01", "2025-02-28", "2025-03-01", "2025-12-31", "2026-01-01"}
+
+	for _, today := range todays {
+		now := date(today)
+		for birth := now.AddDate(-23, 0, -3); !birth.After(now.AddDate(-17, 0, 3)); birth = birth.AddDate(0, 0, 1) {
+			age := Age(birth, now)
+			for _, filter := range []int{18, 19, 20, 21, 22} {
+				onOrBefore, after := ListPlayersInput{AgeFrom: filter, AgeTo: filter}.birthDateBounds(now)
+				matched := !birth.After(*onOrBefore) && birth.After(*after)
+				if matched != (age == filter) {
+					t.Errorf("today %s, born %s (age %d), filter %d: matched = %v",
+						today, birth.Format(dateLayout), age, filter, matched)
+				}
+			}
+		}
+	}
+}
+
+func TestAge(t *testing.T) {
+	tests := []struct {
+		birth, today string
+		want         int
+	}{
+		{"2000-06-15", "2025-06-14", 24},
+		{"2000-06-15", "2025-06-15", 25},
+		{"2000-06-15", "2025-06-16", 25},
+		{"2004-02-29", "2023-02-28", 18},
+		{"2004-02-29", "2023-03-01", 19},
+		{"2004-02-29", "2024-02-29", 20},
+		{"2000-12-31", "2025-01-01", 24},
+	}
+
+	for _, tt := range tests {
+		if got := Age(date(tt.birth), date(tt.today)); got != tt.want {
+			t.Errorf("Age(%s, %s) = %d, want %d", tt.birth, tt.today, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package player
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Bahasa label posisi, dipilih dari header Accept-Language.
+const (
+	LangID = "id"
+	LangEN = "en"
+
+	DefaultLang = LangID
+)
+
+// Kelompok posisi. Kode kelompok juga valid sebagai posisi pemain jika
+// peran detailnya tidak diketahui.
+const (
+	PositionGoalkeeper = "GK"
+	PositionDefender   = "DF"
+	PositionMidfielder = "MF"
+	PositionForward    = "FW"
+)
+
+type position struct {
+	group  string
+	labels map[string]string
+}
+
+var positions = map[string]position{
+	"GK": {PositionGoalkeeper, map[string]string{LangID: "Penjaga Gawang", LangEN: "Goalkeeper"}},
+
+	"DF":  {PositionDefender, map[string]string{LangID: "Bertahan", LangEN: "Defender"}},
+	"CB":  {PositionDefender, map[string]string{LangID: "Bek Tengah", LangEN: "Centre-Back"}},
+	"LB":  {PositionDefender, map[string]string{LangID: "Bek Kiri", LangEN: "Left-Back"}},
+	"RB":  {PositionDefender, map[string]string{LangID: "Bek Kanan", LangEN: "Right-Back"}},
+	"LWB": {PositionDefender, map[string]string{LangID: "Bek Sayap Kiri", LangEN: "Left Wing-Back"}},
+	"RWB": {PositionDefender, map[string]string{LangID: "Bek Sayap Kanan", LangEN: "Right Wing-Back"}},
+
+	"MF": {PositionMidfielder, map[string]string{LangID: "Gelandang", LangEN: "Midfielder"}},
+	"DM": {PositionMidfielder, map[string]string{LangID: "Gelandang Bertahan", LangEN: "Defensive Midfielder"}},
+	"CM": {PositionMidfielder, map[string]string{LangID: "Gelandang Tengah", LangEN: "Central Midfielder"}},
+	"AM": {PositionMidfielder, map[string]string{LangID: "Gelandang Serang", LangEN: "Attacking Midfielder"}},
+	"LM": {PositionMidfielder, map[string]string{LangID: "Gelandang Kiri", LangEN: "Left Midfielder"}},
+	"RM": {PositionMidfielder, map[string]string{LangID: "Gelandang Kanan", LangEN: "Right Midfielder"}},
+
+	"FW": {PositionForward, map[string]string{LangID: "Penyerang", LangEN: "Forward"}},
+	"LW": {PositionForward, map[string]string{LangID: "Sayap Kiri", LangEN: "Left Winger"}},
+	"RW": {PositionForward, map[string]string{LangID: "Sayap Kanan", LangEN: "Right Winger"}},
+	"CF": {PositionForward, map[string]string{LangID: "Penyerang Tengah", LangEN: "Centre-Forward"}},
+	"ST": {PositionForward, map[string]string{LangID: "Striker", LangEN: "Striker"}},
+}
+
+// legacyPositions memetakan teks bebas yang dipakai sebelum ada kode posisi.
+// Kunci dalam huruf kecil.
+var legacyPositions = map[string]string{
+	"penjaga gawang": "GK",
+	"kiper":          "GK",
+	"goalkeeper":     "GK",
+	"bertahan":       "DF",
+	"belakang":       "DF",
+	"bek":            "DF",
+	"defender":       "DF",
+	"gelandang":      "MF",
+	"tengah":         "MF",
+	"midfielder":     "MF",
+	"penyerang":      "FW",
+	"depan":          "FW",
+	"forward":        "FW",
+	"striker":        "ST",
+}
+
+// IsValidPosition tidak membedakan huruf besar/kecil; service menyimpan kode
+// dalam huruf besar.
+func IsValidPosition(code string) bool {
+	_, ok := positions[strings.ToUpper(code)]
+	return ok
+}
+
+// PositionGroup mengembalikan GK, DF, MF, atau FW. Kode yang tidak dikenal
+// menghasilkan string kosong.
+func PositionGroup(code string) string {
+	return positions[strings.ToUpper(code)].group
+}
+
+// PositionsInGroup mengembalikan kode kelompok beserta semua peran detailnya,
+// dipakai untuk filter ?position=DF.
+func PositionsInGroup(group string) []string {
+	codes := []string{}
+	for code, p := range positions {
+		if p.group == group {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// PositionLabel mengembalikan nama posisi dalam bahasa lang. Kode yang tidak
+// dikenal (data lama yang gagal dimigrasi) dikembalikan apa adanya.
+func PositionLabel(code string, lang string) string {
+	p, ok := positions[strings.ToUpper(code)]
+	if !ok {
+		return code
+	}
+	if label, ok := p.labels[lang]; ok {
+		return label
+	}
+	return p.labels[DefaultLang]
+}
+
+// NormalizePosition mengubah kode atau teks lama seperti "Penjaga Gawang"
+// menjadi kode posisi. ok bernilai false jika teks tidak dikenali.
+func NormalizePosition(value string) (code string, ok bool) {
+	value = strings.TrimSpace(value)
+	if IsValidPosition(value) {
+		return strings.ToUpper(value), true
+	}
+	code, ok = legacyPositions[strings.ToLower(value)]
+	return code, ok
+}
+
+// RegisterValidations mendaftarkan tag binding "position".
+func RegisterValidations(v *validator.Validate) error {
+	return v.RegisterValidation("position", func(fl validator.FieldLevel) bool {
+		return IsValidPosition(fl.Field().String())
+	})
+}
@@ -1,15 +1,33 @@
 package player
 
-import "gorm.io/gorm"
+import (
+	"strings"
+	"time"
+
+	"footballteam/helper"
+	"footballteam/team"
+
+	"gorm.io/gorm"
+)
 
 type Repository interface {
-	FindAll() ([]Player, error)
+	FindAll(input ListPlayersInput) ([]Player, int64, error)
 	FindByID(id int) (Player, error)
 	FindByTeamID(teamID int) ([]Player, error)
 	Create(player Player) (Player, error)
 	Update(player Player) (Player, error)
 	Delete(player Player) error
 	IsNumberExistInTeam(teamID, number int) (bool, error)
+	TeamExists(teamID int) (bool, error)
+	FindGoalIDs(playerID int) ([]int, error)
+	FindTrashed(teamIDs []int) ([]Player, error)
+	FindTrashedByID(id int) (Player, error)
+	Restore(player Player) (Player, error)
+	Purge(player Player) error
+	Transfer(player Player, transfer Transfer) (Transfer, error)
+	FindTransfers(playerID int) ([]Transfer, error)
+	FindTransferByID(id int) (Transfer, error)
+	FindLatestTransfer(playerID int) (Transfer, error)
 }
 
 type repository struct {
@@ -20,10 +38,75 @@ func NewRepository(db *gorm.DB) *repository {
 	return &repository{db}
 }
 
-func (r *repository) FindAll() ([]Player, error) {
+func (r *repository) FindAll(input ListPlayersInput) ([]Player, int64, error) {
 	var players []Player
-	err := r.db.Find(&players).Error
-	return players, err
+	var total int64
+
+	query := r.filter(input, time.Now())
+	if err := query.Count(&total).Error; err != nil {
+		return players, total, err
+	}
+
+	err := query.Order(playerOrder(input.Sort)).Order("id").
+		Limit(input.PerPage).Offset(input.Offset()).
+		Find(&players).Error
+	return players, total, err
+}
+
+// filter menerapkan semua filter ListPlayersInput. Filter umur dihitung
+// terhadap today, lihat birthDateBounds.
+func (r *repository) filter(input ListPlayersInput, today time.Time) *gorm.DB {
+	query := r.db.Model(&Player{})
+	if input.Query != "" {
+		query = query.Where("name LIKE ?", helper.LikePattern(input.Query))
+	}
+	if input.TeamID != 0 {
+		query = query.Where("team_id = ?", input.TeamID)
+	}
+	if input.Position != "" {
+		code := strings.ToUpper(input.Position)
+		if PositionGroup(code) == code {
+			query = query.Where("position IN ?", PositionsInGroup(code))
+		} else {
+			query = query.Where("position = ?", code)
+		}
+	}
+	if input.NumberFrom != 0 {
+		query = query.Where("number >= ?", input.NumberFrom)
+	}
+	if input.NumberTo != 0 {
+		query = query.Where("number <= ?", input.NumberTo)
+	}
+	if input.HeightFrom != 0 {
+		query = query.Where("height >= ?", input.HeightFrom)
+	}
+	if input.HeightTo != 0 {
+		query = query.Where("height <= ?", input.HeightTo)
+	}
+	if input.WeightFrom != 0 {
+		query = query.Where("weight >= ?", input.WeightFrom)
+	}
+	if input.WeightTo != 0 {
+		query = query.Where("weight <= ?", input.WeightTo)
+	}
+	bornOnOrBefore, bornAfter := input.birthDateBounds(today)
+	if bornOnOrBefore != nil {
+		query = query.Where("birth_date <= ?", bornOnOrBefore.Format(dateLayout))
+	}
+	if bornAfter != nil {
+		query = query.Where("birth_date > ?", bornAfter.Format(dateLayout))
+	}
+	return query
+}
+
+func playerOrder(sort string) string {
+	if sort == "" {
+		return "name"
+	}
+	if strings.HasPrefix(sort, "-") {
+		return strings.TrimPrefix(sort, "-") + " desc"
+	}
+	return sort
 }
 
 func (r *repository) FindByID(id int) (Player, error) {
@@ -32,9 +115,11 @@ func (r *repository) FindByID(id int) (Player, error) {
 	return player, err
 }
 
+// FindByTeamID mengambil semua pemain tim tanpa paging, diurutkan menurut
+// nomor punggung.
 func (r *repository) FindByTeamID(teamID int) ([]Player, error) {
 	var players []Player
-	err := r.db.Where("team_id = ?", teamID).Find(&players).Error
+	err := r.filter(ListPlayersInput{TeamID: teamID}, time.Now()).Order("number").Find(&players).Error
 	return players, err
 }
 
@@ -63,3 +148,99 @@ func (r *repository) IsNumberExistInTeam(teamID, number int) (bool, error) {
 	}
 	return count > 0, nil
 }
+
+// TeamExists hanya menghitung tim yang belum di-soft delete.
+func (r *repository) TeamExists(teamID int) (bool, error) {
+	var count int64
+	err := r.db.Model(&team.Team{}).
+		Where("id = ?", teamID).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// FindTrashed mengambil data yang sudah di-soft delete, terbaru dulu.
+// teamIDs nil berarti semua tim.
+func (r *repository) FindTrashed(teamIDs []int) ([]Player, error) {
+	var players []Player
+	query := r.db.Unscoped().Where("deleted_at IS NOT NULL")
+	if teamIDs != nil {
+		query = query.Where("team_id IN ?", teamIDs)
+	}
+	err := query.Order("deleted_at desc").Find(&players).Error
+	return players, err
+}
+
+func (r *repository) FindTrashedByID(id int) (Player, error) {
+	var player Player
+	err := r.db.Unscoped().Where("deleted_at IS NOT NULL").First(&player, id).Error
+	return player, err
+}
+
+func (r *repository) Restore(player Player) (Player, error) {
+	err := r.db.Unscoped().Model(&player).Update("deleted_at", nil).Error
+	return player, err
+}
+
+// Purge menghapus permanen, tidak bisa di-restore lagi.
+func (r *repository) Purge(player Player) error {
+	return r.db.Unscoped().Delete(&player).Error
+}
+
+// FindGoalIDs memakai tabel goals langsung karena package match_result
+// bergantung ke package player. Gol yang di-soft delete ikut dihitung.
+func (r *repository) FindGoalIDs(playerID int) ([]int, error) {
+	var ids []int
+	err := r.db.Table("goals").Where("player_id = ?", playerID).Order("id").Pluck("id", &ids).Error
+	return ids, err
+}
+
+// Transfer memindahkan pemain ke tim tujuan dan mencatat history-nya dalam
+// satu transaksi.
+func (r *repository) Transfer(player Player, transfer Transfer) (Transfer, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&player).Updates(map[string]interface{}{
+			"team_id":    transfer.ToTeamID,
+			"number":     transfer.Number,
+			"updated_at": time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&transfer).Error
+	})
+	return transfer, err
+}
+
+// FindTransfers mengambil history transfer pemain, terbaru dulu. Tim yang
+// sudah di-soft delete tetap dimuat supaya namanya bisa ditampilkan.
+func (r *repository) FindTransfers(playerID int) ([]Transfer, error) {
+	var transfers []Transfer
+	err := r.transferQuery().
+		Where("player_id = ?", playerID).
+		Order("date desc, id desc").
+		Find(&transfers).Error
+	return transfers, err
+}
+
+func (r *repository) FindTransferByID(id int) (Transfer, error) {
+	var transfer Transfer
+	err := r.transferQuery().First(&transfer, id).Error
+	return transfer, err
+}
+
+func (r *repository) FindLatestTransfer(playerID int) (Transfer, error) {
+	var transfer Transfer
+	err := r.db.Where("player_id = ?", playerID).Order("date desc, id desc").First(&transfer).Error
+	return transfer, err
+}
+
+func (r *repository) transferQuery() *gorm.DB {
+	unscoped := func(db *gorm.DB) *gorm.DB { return db.Unscoped() }
+	return r.db.
+		Preload("FromTeam", unscoped).
+		Preload("ToTeam", unscoped).
+		Preload("FromTeam.Versions").
+		Preload("ToTeam.Versions")
+}
@@ -2,28 +2,65 @@ package player
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"time"
+
+	"footballteam/audit"
+	"footballteam/helper"
+	"footballteam/user"
+
+	"gorm.io/gorm"
+)
+
+const (
+	auditEntityType         = "player"
+	auditTransferEntityType = "transfer"
+	dateLayout              = "2006-01-02"
+)
+
+var (
+	ErrNumberTaken           = errors.New("nomor punggung sudah digunakan oleh pemain lain di tim ini")
+	ErrTeamNotFound          = errors.New("team not found or deleted")
+	ErrTeamChangeNotAllowed  = errors.New("team cannot be changed directly, use POST /players/:id/transfers")
+	ErrSameTeam              = errors.New("player already belongs to the destination team")
+	ErrInvalidTransferDate   = errors.New("transfer date cannot be in the future or before the player's latest transfer")
+	ErrOutsideTransferWindow = errors.New("transfer date is outside the transfer windows")
 )
 
 type Service interface {
-	GetAllPlayers() ([]Player, error)
+	GetAllPlayers(input ListPlayersInput) ([]Player, int64, error)
 	GetPlayerByID(id int) (Player, error)
 	GetPlayersByTeam(teamID int) ([]Player, error)
 	CreatePlayer(input CreatePlayerInput) (Player, error)
 	UpdatePlayer(id int, input UpdatePlayerInput) (Player, error)
-	DeletePlayer(id int) error
+	DeletePlayer(id int, currentUser user.User) error
+	GetTrashedPlayers(currentUser user.User) ([]Player, error)
+	RestorePlayer(id int, currentUser user.User) (Player, error)
+	PurgePlayer(id int, currentUser user.User) error
+	TransferPlayer(id int, input TransferPlayerInput) (Transfer, error)
+	GetTransfers(playerID int) ([]Transfer, error)
+	GetTeamAsOf(player Player, date time.Time) (int, error)
+}
+
+type Config struct {
+	// TransferWindows kosong berarti transfer boleh kapan saja.
+	TransferWindows []TransferWindow
 }
 
 type service struct {
-	repository Repository
+	repository   Repository
+	auditService audit.Service
+	config       Config
 }
 
-func NewService(repository Repository) *service {
-	return &service{repository}
+func NewService(repository Repository, auditService audit.Service, config Config) *service {
+	return &service{repository, auditService, config}
 }
 
-func (s *service) GetAllPlayers() ([]Player, error) {
-	return s.repository.FindAll()
+func (s *service) GetAllPlayers(input ListPlayersInput) ([]Player, int64, error) {
+	input.Normalize()
+	return s.repository.FindAll(input)
 }
 
 func (s *service) GetPlayerByID(id int) (Player, error) {
@@ -35,26 +72,46 @@ func (s *service) GetPlayersByTeam(teamID int) ([]Player, error) {
 }
 
 func (s *service) CreatePlayer(input CreatePlayerInput) (Player, error) {
+	if !input.User.CanManageTeam(input.TeamID) {
+		return Player{}, user.ErrTeamAccessDenied
+	}
+
+	teamExists, err := s.repository.TeamExists(input.TeamID)
+	if err != nil {
+		return Player{}, err
+	}
+	if !teamExists {
+		return Player{}, ErrTeamNotFound
+	}
+
 	exist, err := s.repository.IsNumberExistInTeam(input.TeamID, input.Number)
 	if err != nil {
 		return Player{}, err
 	}
 	if exist {
-		return Player{}, errors.New("nomor punggung sudah digunakan oleh pemain lain di tim ini")
+		return Player{}, ErrNumberTaken
 	}
 
 	player := Player{
 		Name:      input.Name,
 		Height:    input.Height,
 		Weight:    input.Weight,
-		Position:  input.Position,
+		Position:  strings.ToUpper(input.Position),
 		Number:    input.Number,
+		BirthDate: parseDate(input.BirthDate),
 		TeamID:    input.TeamID,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 
-	return s.repository.Create(player)
+	newPlayer, err := s.repository.Create(player)
+	if err != nil {
+		return newPlayer, err
+	}
+
+	s.auditService.Record(auditActor(input.User), audit.ActionCreate, auditEntityType, newPlayer.ID, nil, newPlayer)
+
+	return newPlayer, nil
 }
 
 func (s *service) UpdatePlayer(id int, input UpdatePlayerInput) (Player, error) {
@@ -62,6 +119,15 @@ func (s *service) UpdatePlayer(id int, input UpdatePlayerInput) (Player, error)
 	if err != nil {
 		return player, err
 	}
+	before := player
+
+	if !input.User.CanManageTeam(player.TeamID) {
+		return player, user.ErrTeamAccessDenied
+	}
+	// Pindah tim harus lewat TransferPlayer supaya tercatat di history
+	if input.TeamID != 0 && input.TeamID != player.TeamID {
+		return player, ErrTeamChangeNotAllowed
+	}
 
 	// Jika mengganti nomor, cek duplikasi
 	if input.Number != 0 && input.Number != player.Number {
@@ -70,7 +136,7 @@ func (s *service) UpdatePlayer(id int, input UpdatePlayerInput) (Player, error)
 			return player, err
 		}
 		if exist {
-			return player, errors.New("nomor punggung sudah digunakan oleh pemain lain di tim ini")
+			return player, ErrNumberTaken
 		}
 	}
 
@@ -84,23 +150,237 @@ func (s *service) UpdatePlayer(id int, input UpdatePlayerInput) (Player, error)
 		player.Weight = input.Weight
 	}
 	if input.Position != "" {
-		player.Position = input.Position
+		player.Position = strings.ToUpper(input.Position)
 	}
 	if input.Number != 0 {
 		player.Number = input.Number
 	}
-	if input.TeamID != 0 {
-		player.TeamID = input.TeamID
+	if input.BirthDate != "" {
+		player.BirthDate = parseDate(input.BirthDate)
 	}
 	player.UpdatedAt = time.Now()
 
-	return s.repository.Update(player)
+	updatedPlayer, err := s.repository.Update(player)
+	if err != nil {
+		return updatedPlayer, err
+	}
+
+	s.auditService.Record(auditActor(input.User), audit.ActionUpdate, auditEntityType, updatedPlayer.ID, before, updatedPlayer)
+
+	return updatedPlayer, nil
 }
 
-func (s *service) DeletePlayer(id int) error {
+func (s *service) DeletePlayer(id int, currentUser user.User) error {
 	player, err := s.repository.FindByID(id)
 	if err != nil {
 		return err
 	}
-	return s.repository.Delete(player)
+	if !currentUser.CanManageTeam(player.TeamID) {
+		return user.ErrTeamAccessDenied
+	}
+
+	if err := s.repository.Delete(player); err != nil {
+		return err
+	}
+
+	s.auditService.Record(auditActor(currentUser), audit.ActionDelete, auditEntityType, player.ID, player, nil)
+
+	return nil
+}
+
+// GetTrashedPlayers untuk manager hanya berisi data klubnya sendiri.
+func (s *service) GetTrashedPlayers(currentUser user.User) ([]Player, error) {
+	teamIDs, restricted := currentUser.ManagedTeamIDs()
+	if restricted && len(teamIDs) == 0 {
+		return []Player{}, nil
+	}
+	return s.repository.FindTrashed(teamIDs)
+}
+
+// RestorePlayer gagal jika timnya sudah dihapus atau nomor punggungnya sudah
+// dipakai pemain lain sejak pemain ini dihapus.
+func (s *service) RestorePlayer(id int, currentUser user.User) (Player, error) {
+	player, err := s.repository.FindTrashedByID(id)
+	if err != nil {
+		return player, err
+	}
+	if !currentUser.CanManageTeam(player.TeamID) {
+		return player, user.ErrTeamAccessDenied
+	}
+
+	teamExists, err := s.repository.TeamExists(player.TeamID)
+	if err != nil {
+		return player, err
+	}
+	if !teamExists {
+		return player, ErrTeamNotFound
+	}
+
+	exist, err := s.repository.IsNumberExistInTeam(player.TeamID, player.Number)
+	if err != nil {
+		return player, err
+	}
+	if exist {
+		return player, ErrNumberTaken
+	}
+
+	restored, err := s.repository.Restore(player)
+	if err != nil {
+		return restored, err
+	}
+
+	s.auditService.Record(auditActor(currentUser), audit.ActionRestore, auditEntityType, restored.ID, nil, nil)
+
+	return restored, nil
+}
+
+func (s *service) PurgePlayer(id int, currentUser user.User) error {
+	player, err := s.repository.FindTrashedByID(id)
+	if err != nil {
+		return err
+	}
+
+	// Gol tetap menyimpan pencetaknya walaupun pemain sudah di-soft delete
+	goalIDs, err := s.repository.FindGoalIDs(player.ID)
+	if err != nil {
+		return err
+	}
+	if err := helper.NewInUseError(map[string][]int{"goals": goalIDs}); err != nil {
+		return err
+	}
+
+	if err := s.repository.Purge(player); err != nil {
+		return err
+	}
+
+	s.auditService.Record(auditActor(currentUser), audit.ActionPurge, auditEntityType, player.ID, player, nil)
+
+	return nil
+}
+
+// TransferPlayer memindahkan pemain ke tim lain. User harus bisa mengelola
+// tim asal dan tim tujuan, nomor punggung dicek di tim tujuan, dan tanggal
+// transfer harus berada di salah satu transfer window.
+func (s *service) TransferPlayer(id int, input TransferPlayerInput) (Transfer, error) {
+	player, err := s.repository.FindByID(id)
+	if err != nil {
+		return Transfer{}, err
+	}
+	before := player
+
+	if !input.User.CanManageTeam(player.TeamID) || !input.User.CanManageTeam(input.ToTeamID) {
+		return Transfer{}, user.ErrTeamAccessDenied
+	}
+	if input.ToTeamID == player.TeamID {
+		return Transfer{}, ErrSameTeam
+	}
+
+	teamExists, err := s.repository.TeamExists(input.ToTeamID)
+	if err != nil {
+		return Transfer{}, err
+	}
+	if !teamExists {
+		return Transfer{}, ErrTeamNotFound
+	}
+
+	date, err := s.transferDate(player.ID, input.Date)
+	if err != nil {
+		return Transfer{}, err
+	}
+	if !inTransferWindow(s.config.TransferWindows, date) {
+		return Transfer{}, fmt.Errorf("%w %v", ErrOutsideTransferWindow, s.config.TransferWindows)
+	}
+
+	number := input.Number
+	if number == 0 {
+		number = player.Number
+	}
+	exist, err := s.repository.IsNumberExistInTeam(input.ToTeamID, number)
+	if err != nil {
+		return Transfer{}, err
+	}
+	if exist {
+		return Transfer{}, ErrNumberTaken
+	}
+
+	transfer, err := s.repository.Transfer(player, Transfer{
+		PlayerID:   player.ID,
+		FromTeamID: player.TeamID,
+		ToTeamID:   input.ToTeamID,
+		Date:       date,
+		Type:       input.Type,
+		Fee:        input.Fee,
+		Number:     number,
+		CreatedAt:  time.Now(),
+	})
+	if err != nil {
+		return transfer, err
+	}
+
+	after := player
+	after.TeamID = transfer.ToTeamID
+	after.Number = transfer.Number
+	actor := auditActor(input.User)
+	s.auditService.Record(actor, audit.ActionUpdate, auditEntityType, player.ID, before, after)
+	s.auditService.Record(actor, audit.ActionCreate, auditTransferEntityType, transfer.ID, nil, transfer)
+
+	return s.repository.FindTransferByID(transfer.ID)
+}
+
+// transferDate default hari ini. Tanggal tidak boleh di masa depan (pemain
+// langsung dipindah) dan tidak boleh sebelum transfer terakhir pemain.
+func (s *service) transferDate(playerID int, value string) (time.Time, error) {
+	today := parseDate(time.Now().Format(dateLayout))
+	if value == "" {
+		return *today, nil
+	}
+
+	date := parseDate(value)
+	if date == nil || date.After(*today) {
+		return time.Time{}, ErrInvalidTransferDate
+	}
+
+	latest, err := s.repository.FindLatestTransfer(playerID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return time.Time{}, err
+	}
+	if err == nil && date.Before(latest.Date) {
+		return time.Time{}, ErrInvalidTransferDate
+	}
+
+	return *date, nil
+}
+
+func (s *service) GetTransfers(playerID int) ([]Transfer, error) {
+	if _, err := s.repository.FindByID(playerID); err != nil {
+		return nil, err
+	}
+	return s.repository.FindTransfers(playerID)
+}
+
+// GetTeamAsOf mengembalikan ID tim pemain pada tanggal tertentu, misalnya
+// tanggal pertandingan, berdasarkan history transfer.
+func (s *service) GetTeamAsOf(player Player, date time.Time) (int, error) {
+	transfers, err := s.repository.FindTransfers(player.ID)
+	if err != nil {
+		return 0, err
+	}
+	return teamAsOf(player, transfers, date), nil
+}
+
+// parseDate mengembalikan nil untuk string kosong. Format sudah divalidasi
+// lewat binding datetime.
+func parseDate(value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+	date, err := time.ParseInLocation(dateLayout, value, time.Local)
+	if err != nil {
+		return nil
+	}
+	return &date
+}
+
+func auditActor(currentUser user.User) audit.Actor {
+	return audit.UserActor(currentUser.ID, currentUser.Name)
 }
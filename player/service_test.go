@@ -0,0 +1,69 @@
+package player
+
+import (
+	"errors"
+	"testing"
+
+	"footballteam/audit"
+	"footballteam/user"
+)
+
+type fakeRepository struct {
+	Repository
+	teams   map[int]bool
+	numbers map[int][]int
+	created []Player
+}
+
+func (r *fakeRepository) TeamExists(teamID int) (bool, error) {
+	return r.teams[teamID], nil
+}
+
+func (r *fakeRepository) IsNumberExistInTeam(teamID, number int) (bool, error) {
+	for _, n := range r.numbers[teamID] {
+		if n == number {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeRepository) Create(player Player) (Player, error) {
+	player.ID = len(r.created) + 1
+	r.created = append(r.created, player)
+	return player, nil
+}
+
+type fakeAuditService struct{ audit.Service }
+
+func (fakeAuditService) Record(audit.Actor, string, string, int, interface{}, interface{}) {}
+
+func TestCreatePlayerErrors(t *testing.T) {
+	admin := user.User{ID: 1, Role: user.RoleAdmin}
+
+	tests := []struct {
+		name    string
+		teamID  int
+		number  int
+		wantErr error
+	}{
+		{"created", 1, 10, nil},
+		{"number taken", 1, 9, ErrNumberTaken},
+		{"team missing", 5, 9, ErrTeamNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := &fakeRepository{teams: map[int]bool{1: true}, numbers: map[int][]int{1: {9}}}
+			s := NewService(repository, fakeAuditService{}, Config{})
+
+			_, err := s.CreatePlayer(CreatePlayerInput{Name: "Rizky", Position: "ST", Number: tt.number, TeamID: tt.teamID, User: admin})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreatePlayer error = %v, want %v", err, tt.wantErr)
+			}
+			if created := len(repository.created) == 1; created != (tt.wantErr == nil) {
+				t.Errorf("created = %v", repository.created)
+			}
+		})
+	}
+}
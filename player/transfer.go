@@ -0,0 +1,110 @@
+package player
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TransferWindow adalah rentang tanggal tahunan (inklusif) saat transfer
+// boleh dilakukan. Rentang boleh melewati akhir tahun, misalnya 12-15:01-15.
+type TransferWindow struct {
+	StartMonth time.Month
+	StartDay   int
+	EndMonth   time.Month
+	EndDay     int
+}
+
+// ParseTransferWindows membaca format "MM-DD:MM-DD" yang dipisah koma,
+// misalnya "07-01:08-31,01-01:01-31". String kosong berarti tidak ada
+// pembatasan.
+func ParseTransferWindows(value string) ([]TransferWindow, error) {
+	var windows []TransferWindow
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		start, end, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid transfer window %q: want MM-DD:MM-DD", part)
+		}
+		startDate, err := time.Parse("01-02", strings.TrimSpace(start))
+		if err != nil {
+			return nil, fmt.Errorf("invalid transfer window %q: %w", part, err)
+		}
+		endDate, err := time.Parse("01-02", strings.TrimSpace(end))
+		if err != nil {
+			return nil, fmt.Errorf("invalid transfer window %q: %w", part, err)
+		}
+
+		windows = append(windows, TransferWindow{startDate.Month(), startDate.Day(), endDate.Month(), endDate.Day()})
+	}
+	return windows, nil
+}
+
+// Contains hanya melihat bulan dan hari, jadi window berlaku setiap tahun.
+func (w TransferWindow) Contains(date time.Time) bool {
+	day := int(date.Month())*100 + date.Day()
+	start := int(w.StartMonth)*100 + w.StartDay
+	end := int(w.EndMonth)*100 + w.EndDay
+
+	if start <= end {
+		return start <= day && day <= end
+	}
+	return day >= start || day <= end
+}
+
+func (w TransferWindow) String() string {
+	return fmt.Sprintf("%02d-%02d:%02d-%02d", w.StartMonth, w.StartDay, w.EndMonth, w.EndDay)
+}
+
+// inTransferWindow selalu true jika tidak ada window yang dikonfigurasi.
+func inTransferWindow(windows []TransferWindow, date time.Time) bool {
+	if len(windows) == 0 {
+		return true
+	}
+	for _, w := range windows {
+		if w.Contains(date) {
+			return true
+		}
+	}
+	return false
+}
+
+// teamAsOf mencari tim pemain pada tanggal tertentu dari history transfer.
+// Transfer pada tanggal itu sudah dihitung; transfer di tanggal yang sama
+// diurutkan berdasarkan ID. Sebelum transfer pertama pemain masih di tim asal
+// transfer tersebut, dan tanpa history sama sekali dipakai tim sekarang.
+func teamAsOf(player Player, transfers []Transfer, date time.Time) int {
+	day := date.Format(dateLayout)
+
+	var latest, earliest *Transfer
+	for i := range transfers {
+		t := &transfers[i]
+		if t.Date.Format(dateLayout) <= day && (latest == nil || transferAfter(*t, *latest)) {
+			latest = t
+		}
+		if earliest == nil || transferAfter(*earliest, *t) {
+			earliest = t
+		}
+	}
+
+	switch {
+	case latest != nil:
+		return latest.ToTeamID
+	case earliest != nil:
+		return earliest.FromTeamID
+	default:
+		return player.TeamID
+	}
+}
+
+func transferAfter(a, b Transfer) bool {
+	ad, bd := a.Date.Format(dateLayout), b.Date.Format(dateLayout)
+	if ad != bd {
+		return ad > bd
+	}
+	return a.ID > b.ID
+}
@@ -0,0 +1,156 @@
+package player
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTransferWindows(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{"", "[]", false},
+		{" , ", "[]", false},
+		{"07-01:08-31", "[07-01:08-31]", false},
+		{"07-01:08-31, 01-01:01-31", "[07-01:08-31 01-01:01-31]", false},
+		{"12-15:01-15", "[12-15:01-15]", false},
+		{"02-29:03-01", "[02-29:03-01]", false},
+		{"07-01", "", true},
+		{"7-1:8-31", "", true},
+		{"13-01:01-31", "", true},
+		{"02-30:03-01", "", true},
+		{"07-01:08-31,bad", "", true},
+	}
+
+	for _, tt := range tests {
+		windows, err := ParseTransferWindows(tt.value)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseTransferWindows(%q) = %v, want error", tt.value, windows)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseTransferWindows(%q) error: %v", tt.value, err)
+			continue
+		}
+
+		got := "[]"
+		if len(windows) > 0 {
+			got = formatWindows(windows)
+		}
+		if got != tt.want {
+			t.Errorf("ParseTransferWindows(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
+
+func formatWindows(windows []TransferWindow) string {
+	s := "["
+	for i, w := range windows {
+		if i > 0 {
+			s += " "
+		}
+		s += w.String()
+	}
+	return s + "]"
+}
+
+func TestTransferWindowContains(t *testing.T) {
+	summer := TransferWindow{time.July, 1, time.August, 31}
+	winter := TransferWindow{time.December, 15, time.January, 15}
+	leap := TransferWindow{time.February, 29, time.February, 29}
+
+	tests := []struct {
+		window TransferWindow
+		date   string
+		want   bool
+	}{
+		{summer, "2025-06-30", false},
+		{summer, "2025-07-01", true},
+		{summer, "2025-07-31", true},
+		{summer, "2025-08-31", true},
+		{summer, "2025-09-01", false},
+
+		// Melewati akhir tahun
+		{winter, "2025-12-14", false},
+		{winter, "2025-12-15", true},
+		{winter, "2025-12-31", true},
+		{winter, "2026-01-01", true},
+		{winter, "2026-01-15", true},
+		{winter, "2026-01-16", false},
+		{winter, "2026-06-01", false},
+
+		{leap, "2024-02-29", true},
+		{leap, "2024-02-28", false},
+		{leap, "2025-03-01", false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.window.Contains(date(tt.date)); got != tt.want {
+			t.Errorf("%s.Contains(%s) = %v, want %v", tt.window, tt.date, got, tt.want)
+		}
+	}
+}
+
+func TestInTransferWindow(t *testing.T) {
+	windows := []TransferWindow{
+		{time.January, 1, time.January, 31},
+		{time.July, 1, time.August, 31},
+	}
+
+	tests := []struct {
+		windows []TransferWindow
+		date    string
+		want    bool
+	}{
+		{nil, "2025-03-15", true},
+		{windows, "2025-01-01", true},
+		{windows, "2025-01-31", true},
+		{windows, "2025-02-01", false},
+		{windows, "2025-06-30", false},
+		{windows, "2025-07-01", true},
+		{windows, "2025-08-31", true},
+		{windows, "2025-12-31", false},
+	}
+
+	for _, tt := range tests {
+		if got := inTransferWindow(tt.windows, date(tt.date)); got != tt.want {
+			t.Errorf("inTransferWindow(%v, %s) = %v, want %v", tt.windows, tt.date, got, tt.want)
+		}
+	}
+}
+
+func TestTeamAsOf(t *testing.T) {
+	player := Player{ID: 1, TeamID: 3}
+	// Urutan sama seperti FindTransfers: terbaru dulu
+	transfers := []Transfer{
+		{ID: 12, FromTeamID: 2, ToTeamID: 3, Date: date("2024-07-01")},
+		{ID: 11, FromTeamID: 4, ToTeamID: 2, Date: date("2023-01-15")},
+		{ID: 10, FromTeamID: 1, ToTeamID: 4, Date: date("2023-01-15")},
+	}
+
+	tests := []struct {
+		name      string
+		transfers []Transfer
+		date      string
+		want      int
+	}{
+		{"no transfers", nil, "2020-01-01", 3},
+		{"before first transfer", transfers, "2023-01-14", 1},
+		{"on transfer date, later id wins", transfers, "2023-01-15", 2},
+		{"between transfers", transfers, "2024-06-30", 2},
+		{"on latest transfer date", transfers, "2024-07-01", 3},
+		{"after latest transfer", transfers, "2025-03-01", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := teamAsOf(player, tt.transfers, date(tt.date)); got != tt.want {
+				t.Errorf("teamAsOf(%s) = %d, want %d", tt.date, got, tt.want)
+			}
+		})
+	}
+}
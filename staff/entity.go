@@ -0,0 +1,42 @@
+package staff
+
+import (
+	"time"
+
+	"footballteam/team"
+
+	"gorm.io/gorm"
+)
+
+const (
+	RoleHeadCoach       = "head_coach"
+	RoleAssistantCoach  = "assistant_coach"
+	RoleGoalkeeperCoach = "goalkeeper_coach"
+	RoleFitnessCoach    = "fitness_coach"
+	RolePhysio          = "physio"
+	RoleDoctor          = "doctor"
+	RoleKitManager      = "kit_manager"
+	RoleAnalyst         = "analyst"
+)
+
+type Staff struct {
+	ID        int        `gorm:"primaryKey;autoIncrement"`
+	Name      string     `gorm:"type:varchar(100);not null"`
+	Role      string     `gorm:"type:varchar(30);not null"`
+	Email     string     `gorm:"type:varchar(100)"`
+	Phone     string     `gorm:"type:varchar(30)"`
+	StartDate time.Time  `gorm:"type:date;not null"`
+	EndDate   *time.Time `gorm:"type:date"` // kosong selama masih bertugas
+	TeamID    int        `gorm:"not null;index"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+
+	// Relasi, dipakai untuk foreign key. Tim yang masih punya staf tidak bisa
+	// dihapus permanen.
+	Team *team.Team `gorm:"constraint:OnUpdate:CASCADE,OnDelete:RESTRICT"`
+}
+
+func (Staff) TableName() string {
+	return "staff"
+}
@@ -0,0 +1,52 @@
+package staff
+
+import "time"
+
+type StaffFormatter struct {
+	ID        int     `json:"id"`
+	Name      string  `json:"name"`
+	Role      string  `json:"role"`
+	Email     string  `json:"email"`
+	Phone     string  `json:"phone"`
+	StartDate string  `json:"start_date"`
+	EndDate   *string `json:"end_date"`
+	TeamID    int     `json:"team_id"`
+}
+
+func FormatStaff(staff Staff) StaffFormatter {
+	formatter := StaffFormatter{
+		ID:        staff.ID,
+		Name:      staff.Name,
+		Role:      staff.Role,
+		Email:     staff.Email,
+		Phone:     staff.Phone,
+		StartDate: staff.StartDate.Format(dateLayout),
+		TeamID:    staff.TeamID,
+	}
+	if staff.EndDate != nil {
+		endDate := staff.EndDate.Format(dateLayout)
+		formatter.EndDate = &endDate
+	}
+	return formatter
+}
+
+func FormatStaffList(staffList []Staff) []StaffFormatter {
+	formatted := []StaffFormatter{}
+	for _, s := range staffList {
+		formatted = append(formatted, FormatStaff(s))
+	}
+	return formatted
+}
+
+type TrashedStaffFormatter struct {
+	StaffFormatter
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+func FormatTrashedStaff(staffList []Staff) []TrashedStaffFormatter {
+	formatted := []TrashedStaffFormatter{}
+	for _, s := range staffList {
+		formatted = append(formatted, TrashedStaffFormatter{FormatStaff(s), s.DeletedAt.Time})
+	}
+	return formatted
+}
@@ -0,0 +1,30 @@
+package staff
+
+import "footballteam/user"
+
+// Tanggal memakai format YYYY-MM-DD. EndDate kosong berarti staf masih
+// bertugas.
+type CreateStaffInput struct {
+	Name      string    `json:"name" binding:"required,max=100"`
+	Role      string    `json:"role" binding:"required,oneof=head_coach assistant_coach goalkeeper_coach fitness_coach physio doctor kit_manager analyst"`
+	Email     string    `json:"email" binding:"omitempty,email,max=100"`
+	Phone     string    `json:"phone" binding:"max=30"`
+	StartDate string    `json:"start_date" binding:"required,datetime=2006-01-02"`
+	EndDate   string    `json:"end_date" binding:"omitempty,datetime=2006-01-02"`
+	TeamID    int       `json:"team_id" binding:"required"`
+	User      user.User `json:"-"`
+}
+
+// UpdateStaffInput: field kosong tidak diubah. ClearEndDate mengosongkan
+// end_date, misalnya jika kontrak staf diperpanjang.
+type UpdateStaffInput struct {
+	Name         string    `json:"name" binding:"max=100"`
+	Role         string    `json:"role" binding:"omitempty,oneof=head_coach assistant_coach goalkeeper_coach fitness_coach physio doctor kit_manager analyst"`
+	Email        string    `json:"email" binding:"omitempty,email,max=100"`
+	Phone        string    `json:"phone" binding:"max=30"`
+	StartDate    string    `json:"start_date" binding:"omitempty,datetime=2006-01-02"`
+	EndDate      string    `json:"end_date" binding:"omitempty,datetime=2006-01-02"`
+	ClearEndDate bool      `json:"clear_end_date"`
+	TeamID       int       `json:"team_id"`
+	User         user.User `json:"-"`
+}
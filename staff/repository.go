@@ -0,0 +1,117 @@
+package staff
+
+import (
+	"time"
+
+	"footballteam/team"
+
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	FindAll() ([]Staff, error)
+	FindByID(id int) (Staff, error)
+	FindByTeamID(teamID int) ([]Staff, error)
+	FindActiveByTeamID(teamID int, date time.Time) ([]Staff, error)
+	Create(staff Staff) (Staff, error)
+	Update(staff Staff) (Staff, error)
+	Delete(staff Staff) error
+	TeamExists(teamID int) (bool, error)
+	FindTrashed(teamIDs []int) ([]Staff, error)
+	FindTrashedByID(id int) (Staff, error)
+	Restore(staff Staff) (Staff, error)
+	Purge(staff Staff) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *repository {
+	return &repository{db}
+}
+
+func (r *repository) FindAll() ([]Staff, error) {
+	var staffList []Staff
+	err := r.db.Order("team_id").Order("start_date").Find(&staffList).Error
+	return staffList, err
+}
+
+func (r *repository) FindByID(id int) (Staff, error) {
+	var staff Staff
+	err := r.db.First(&staff, id).Error
+	return staff, err
+}
+
+// FindByTeamID ikut mengambil staf yang sudah tidak bertugas.
+func (r *repository) FindByTeamID(teamID int) ([]Staff, error) {
+	var staffList []Staff
+	err := r.db.Where("team_id = ?", teamID).Order("start_date").Find(&staffList).Error
+	return staffList, err
+}
+
+// FindActiveByTeamID mengambil staf yang bertugas pada tanggal date.
+func (r *repository) FindActiveByTeamID(teamID int, date time.Time) ([]Staff, error) {
+	var staffList []Staff
+	day := date.Format(dateLayout)
+	err := r.db.
+		Where("team_id = ? AND start_date <= ?", teamID, day).
+		Where("end_date IS NULL OR end_date >= ?", day).
+		Order("start_date").
+		Find(&staffList).Error
+	return staffList, err
+}
+
+func (r *repository) Create(staff Staff) (Staff, error) {
+	err := r.db.Create(&staff).Error
+	return staff, err
+}
+
+func (r *repository) Update(staff Staff) (Staff, error) {
+	err := r.db.Save(&staff).Error
+	return staff, err
+}
+
+func (r *repository) Delete(staff Staff) error {
+	return r.db.Delete(&staff).Error
+}
+
+// TeamExists hanya menghitung tim yang belum di-soft delete.
+func (r *repository) TeamExists(teamID int) (bool, error) {
+	var count int64
+	err := r.db.Model(&team.Team{}).
+		Where("id = ?", teamID).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// FindTrashed mengambil data yang sudah di-soft delete, terbaru dulu.
+// teamIDs nil berarti semua tim.
+func (r *repository) FindTrashed(teamIDs []int) ([]Staff, error) {
+	var staffList []Staff
+	query := r.db.Unscoped().Where("deleted_at IS NOT NULL")
+	if teamIDs != nil {
+		query = query.Where("team_id IN ?", teamIDs)
+	}
+	err := query.Order("deleted_at desc").Find(&staffList).Error
+	return staffList, err
+}
+
+func (r *repository) FindTrashedByID(id int) (Staff, error) {
+	var staff Staff
+	err := r.db.Unscoped().Where("deleted_at IS NOT NULL").First(&staff, id).Error
+	return staff, err
+}
+
+func (r *repository) Restore(staff Staff) (Staff, error) {
+	err := r.db.Unscoped().Model(&staff).Update("deleted_at", nil).Error
+	return staff, err
+}
+
+// Purge menghapus permanen, tidak bisa di-restore lagi.
+func (r *repository) Purge(staff Staff) error {
+	return r.db.Unscoped().Delete(&staff).Error
+}
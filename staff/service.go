@@ -0,0 +1,235 @@
+package staff
+
+import (
+	"errors"
+	"time"
+
+	"footballteam/audit"
+	"footballteam/user"
+)
+
+const (
+	auditEntityType = "staff"
+	dateLayout      = "2006-01-02"
+)
+
+var (
+	ErrTeamNotFound = errors.New("team not found or deleted")
+	ErrInvalidDates = errors.New("end_date must not be before start_date")
+)
+
+type Service interface {
+	GetAllStaff() ([]Staff, error)
+	GetStaffByID(id int) (Staff, error)
+	GetStaffByTeam(teamID int) ([]Staff, error)
+	GetActiveStaffByTeam(teamID int) ([]Staff, error)
+	CreateStaff(input CreateStaffInput) (Staff, error)
+	UpdateStaff(id int, input UpdateStaffInput) (Staff, error)
+	DeleteStaff(id int, currentUser user.User) error
+	GetTrashedStaff(currentUser user.User) ([]Staff, error)
+	RestoreStaff(id int, currentUser user.User) (Staff, error)
+	PurgeStaff(id int, currentUser user.User) error
+}
+
+type service struct {
+	repository   Repository
+	auditService audit.Service
+}
+
+func NewService(repository Repository, auditService audit.Service) *service {
+	return &service{repository, auditService}
+}
+
+func (s *service) GetAllStaff() ([]Staff, error) {
+	return s.repository.FindAll()
+}
+
+func (s *service) GetStaffByID(id int) (Staff, error) {
+	return s.repository.FindByID(id)
+}
+
+func (s *service) GetStaffByTeam(teamID int) ([]Staff, error) {
+	return s.repository.FindByTeamID(teamID)
+}
+
+// GetActiveStaffByTeam hanya mengambil staf yang bertugas hari ini.
+func (s *service) GetActiveStaffByTeam(teamID int) ([]Staff, error) {
+	return s.repository.FindActiveByTeamID(teamID, time.Now())
+}
+
+func (s *service) CreateStaff(input CreateStaffInput) (Staff, error) {
+	if !input.User.CanManageTeam(input.TeamID) {
+		return Staff{}, user.ErrTeamAccessDenied
+	}
+
+	if err := s.checkTeam(input.TeamID); err != nil {
+		return Staff{}, err
+	}
+
+	// Format tanggal sudah divalidasi lewat binding datetime
+	startDate, _ := time.ParseInLocation(dateLayout, input.StartDate, time.Local)
+	staff := Staff{
+		Name:      input.Name,
+		Role:      input.Role,
+		Email:     input.Email,
+		Phone:     input.Phone,
+		StartDate: startDate,
+		TeamID:    input.TeamID,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if input.EndDate != "" {
+		endDate, _ := time.ParseInLocation(dateLayout, input.EndDate, time.Local)
+		staff.EndDate = &endDate
+	}
+	if staff.EndDate != nil && staff.EndDate.Before(staff.StartDate) {
+		return Staff{}, ErrInvalidDates
+	}
+
+	newStaff, err := s.repository.Create(staff)
+	if err != nil {
+		return newStaff, err
+	}
+
+	s.auditService.Record(auditActor(input.User), audit.ActionCreate, auditEntityType, newStaff.ID, nil, newStaff)
+
+	return newStaff, nil
+}
+
+func (s *service) UpdateStaff(id int, input UpdateStaffInput) (Staff, error) {
+	staff, err := s.repository.FindByID(id)
+	if err != nil {
+		return staff, err
+	}
+	before := staff
+
+	// Manager harus memiliki klub asal dan klub tujuan (jika staf dipindah)
+	if !input.User.CanManageTeam(staff.TeamID) {
+		return staff, user.ErrTeamAccessDenied
+	}
+	if input.TeamID != 0 && !input.User.CanManageTeam(input.TeamID) {
+		return staff, user.ErrTeamAccessDenied
+	}
+
+	if input.TeamID != 0 && input.TeamID != staff.TeamID {
+		if err := s.checkTeam(input.TeamID); err != nil {
+			return staff, err
+		}
+		staff.TeamID = input.TeamID
+	}
+	if input.Name != "" {
+		staff.Name = input.Name
+	}
+	if input.Role != "" {
+		staff.Role = input.Role
+	}
+	if input.Email != "" {
+		staff.Email = input.Email
+	}
+	if input.Phone != "" {
+		staff.Phone = input.Phone
+	}
+	if input.StartDate != "" {
+		staff.StartDate, _ = time.ParseInLocation(dateLayout, input.StartDate, time.Local)
+	}
+	if input.ClearEndDate {
+		staff.EndDate = nil
+	} else if input.EndDate != "" {
+		endDate, _ := time.ParseInLocation(dateLayout, input.EndDate, time.Local)
+		staff.EndDate = &endDate
+	}
+	if staff.EndDate != nil && staff.EndDate.Before(staff.StartDate) {
+		return before, ErrInvalidDates
+	}
+	staff.UpdatedAt = time.Now()
+
+	updatedStaff, err := s.repository.Update(staff)
+	if err != nil {
+		return updatedStaff, err
+	}
+
+	s.auditService.Record(auditActor(input.User), audit.ActionUpdate, auditEntityType, updatedStaff.ID, before, updatedStaff)
+
+	return updatedStaff, nil
+}
+
+func (s *service) DeleteStaff(id int, currentUser user.User) error {
+	staff, err := s.repository.FindByID(id)
+	if err != nil {
+		return err
+	}
+	if !currentUser.CanManageTeam(staff.TeamID) {
+		return user.ErrTeamAccessDenied
+	}
+
+	if err := s.repository.Delete(staff); err != nil {
+		return err
+	}
+
+	s.auditService.Record(auditActor(currentUser), audit.ActionDelete, auditEntityType, staff.ID, staff, nil)
+
+	return nil
+}
+
+// GetTrashedStaff untuk manager hanya berisi data klubnya sendiri.
+func (s *service) GetTrashedStaff(currentUser user.User) ([]Staff, error) {
+	teamIDs, restricted := currentUser.ManagedTeamIDs()
+	if restricted && len(teamIDs) == 0 {
+		return []Staff{}, nil
+	}
+	return s.repository.FindTrashed(teamIDs)
+}
+
+// RestoreStaff gagal jika timnya sudah dihapus.
+func (s *service) RestoreStaff(id int, currentUser user.User) (Staff, error) {
+	staff, err := s.repository.FindTrashedByID(id)
+	if err != nil {
+		return staff, err
+	}
+	if !currentUser.CanManageTeam(staff.TeamID) {
+		return staff, user.ErrTeamAccessDenied
+	}
+
+	if err := s.checkTeam(staff.TeamID); err != nil {
+		return staff, err
+	}
+
+	restored, err := s.repository.Restore(staff)
+	if err != nil {
+		return restored, err
+	}
+
+	s.auditService.Record(auditActor(currentUser), audit.ActionRestore, auditEntityType, restored.ID, nil, nil)
+
+	return restored, nil
+}
+
+func (s *service) PurgeStaff(id int, currentUser user.User) error {
+	staff, err := s.repository.FindTrashedByID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repository.Purge(staff); err != nil {
+		return err
+	}
+
+	s.auditService.Record(auditActor(currentUser), audit.ActionPurge, auditEntityType, staff.ID, staff, nil)
+
+	return nil
+}
+
+func (s *service) checkTeam(teamID int) error {
+	exists, err := s.repository.TeamExists(teamID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrTeamNotFound
+	}
+	return nil
+}
+
+func auditActor(currentUser user.User) audit.Actor {
+	return audit.UserActor(currentUser.ID, currentUser.Name)
+}
@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+)
+
+const (
+	DefaultLocalDir     = "uploads"
+	DefaultLocalBaseURL = "/api/v1/uploads"
+)
+
+// local menyimpan file di disk. Hanya cocok untuk satu instance, atau jika
+// dir adalah volume yang di-share antar instance.
+type local struct {
+	dir     string
+	baseURL string
+}
+
+func NewLocal(dir string, baseURL string) *local {
+	if dir == "" {
+		dir = DefaultLocalDir
+	}
+	if baseURL == "" {
+		baseURL = DefaultLocalBaseURL
+	}
+
+	return &local{dir, baseURL}
+}
+
+// Dir dipakai main untuk menyajikan file lewat route static.
+func (l *local) Dir() string {
+	return l.dir
+}
+
+// Put menulis ke file sementara lalu di-rename supaya file tidak pernah
+// terbaca setengah jadi.
+func (l *local) Put(key string, data []byte, contentType string) error {
+	name, err := l.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(name), os.ModePerm); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), name)
+}
+
+func (l *local) Exists(key string) (bool, error) {
+	name, err := l.path(key)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = os.Stat(name)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Delete tidak menganggap file yang sudah tidak ada sebagai error.
+func (l *local) Delete(key string) error {
+	name, err := l.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(name); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (l *local) URL(key string) string {
+	return joinURL(l.baseURL, key)
+}
+
+func (l *local) path(key string) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(l.dir, filepath.FromSlash(key)), nil
+}
@@ -0,0 +1,240 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+var ErrS3Request = errors.New("object storage request failed")
+
+const (
+	DefaultS3Region  = "us-east-1"
+	DefaultS3Timeout = 30 * time.Second
+
+	amzDateLayout  = "20060102T150405Z"
+	amzShortLayout = "20060102"
+)
+
+// S3Config berlaku untuk AWS S3 maupun layanan yang kompatibel seperti MinIO.
+// Bucket diakses dengan path-style ({endpoint}/{bucket}/{key}) karena itu
+// yang didukung semua implementasi.
+type S3Config struct {
+	Endpoint        string // contoh: https://s3.ap-southeast-1.amazonaws.com atau http://localhost:9000
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// PublicURL adalah base URL untuk link file, misalnya CDN. Jika kosong
+	// dipakai {endpoint}/{bucket}.
+	PublicURL  string
+	HTTPClient *http.Client
+}
+
+type s3Storage struct {
+	config S3Config
+	now    func() time.Time
+}
+
+func NewS3(config S3Config) *s3Storage {
+	config.Endpoint = strings.TrimSuffix(config.Endpoint, "/")
+	if config.Region == "" {
+		config.Region = DefaultS3Region
+	}
+	if config.PublicURL == "" {
+		config.PublicURL = config.Endpoint + "/" + config.Bucket
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: DefaultS3Timeout}
+	}
+
+	return &s3Storage{config, time.Now}
+}
+
+func (s *s3Storage) Put(key string, data []byte, contentType string) error {
+	req, err := s.newRequest(http.MethodPut, key, data)
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := s.do(req, data)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return s.responseError(resp)
+	}
+	return nil
+}
+
+func (s *s3Storage) Exists(key string) (bool, error) {
+	req, err := s.newRequest(http.MethodHead, key, nil)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := s.do(req, nil)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	}
+	return false, s.responseError(resp)
+}
+
+// Delete di S3 sudah idempotent; 404 tetap diterima untuk layanan kompatibel
+// yang membalas begitu.
+func (s *s3Storage) Delete(key string) error {
+	req, err := s.newRequest(http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.do(req, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	}
+	return s.responseError(resp)
+}
+
+// URL meng-escape key dengan aturan yang sama seperti request ke bucket.
+func (s *s3Storage) URL(key string) string {
+	return joinURL(s.config.PublicURL, escapePath(key))
+}
+
+func (s *s3Storage) newRequest(method string, key string, body []byte) (*http.Request, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+
+	target := s.config.Endpoint + "/" + escapePath(s.config.Bucket+"/"+key)
+	return http.NewRequest(method, target, bytes.NewReader(body))
+}
+
+func (s *s3Storage) do(req *http.Request, body []byte) (*http.Response, error) {
+	s.sign(req, body, s.now().UTC())
+
+	resp, err := s.config.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrS3Request, err)
+	}
+	return resp, nil
+}
+
+func (s *s3Storage) responseError(resp *http.Response) error {
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("%w: %s %s", ErrS3Request, resp.Status, strings.TrimSpace(string(message)))
+}
+
+// sign menambahkan header Authorization AWS Signature Version 4. Semua header
+// yang sudah terpasang di request ikut ditandatangani.
+func (s *s3Storage) sign(req *http.Request, body []byte, now time.Time) {
+	payloadHash := sha256Hex(body)
+	amzDate := now.Format(amzDateLayout)
+	shortDate := now.Format(amzShortLayout)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	canonical, signedHeaders := canonicalRequest(req, payloadHash)
+	scope := shortDate + "/" + s.config.Region + "/s3/aws4_request"
+
+	key := hmacSHA256([]byte("AWS4"+s.config.SecretAccessKey), shortDate)
+	key = hmacSHA256(key, s.config.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign(amzDate, scope, canonical)))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.config.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// canonicalRequest mengembalikan canonical request SigV4 beserta daftar
+// signed headers-nya.
+func canonicalRequest(req *http.Request, payloadHash string) (string, string) {
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.Join(values, ",")
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	return strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n"), signedHeaders
+}
+
+func stringToSign(amzDate string, scope string, canonicalRequest string) string {
+	return strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+}
+
+// escapePath meng-encode tiap segmen path sesuai aturan URI encoding SigV4
+// (hanya karakter unreserved yang dibiarkan).
+func escapePath(p string) string {
+	var b strings.Builder
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		if c == '/' || c == '-' || c == '_' || c == '.' || c == '~' ||
+			('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
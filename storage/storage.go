@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"errors"
+	"path"
+	"strings"
+)
+
+var ErrInvalidKey = errors.New("invalid storage key")
+
+// Storage menyimpan file upload berdasarkan key relatif, misalnya
+// "logo/<hash>.png". Database hanya menyimpan key; URL publik dibentuk saat
+// respon diformat sehingga backend bisa diganti tanpa mengubah data.
+type Storage interface {
+	Put(key string, data []byte, contentType string) error
+	Exists(key string) (bool, error)
+	Delete(key string) error
+	URL(key string) string
+}
+
+// validateKey menolak key absolut atau yang keluar dari root lewat "..".
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key || key == ".." || strings.HasPrefix(key, "../") {
+		return ErrInvalidKey
+	}
+	return nil
+}
+
+func joinURL(base string, key string) string {
+	return strings.TrimSuffix(base, "/") + "/" + key
+}
@@ -3,17 +3,49 @@ package team
 import (
 	"time"
 
+	"footballteam/venue"
+
 	"gorm.io/gorm"
 )
 
 type Team struct {
-	ID          	int            `gorm:"primaryKey;autoIncrement"`
-	Name        	string         `gorm:"size:100;not null"`
-	Logo        	string         `gorm:"size:255"`
-	YearFounded 	int            `gorm:"not null"`
-	Address     	string         `gorm:"size:255"`
-	City        	string         `gorm:"size:100"`
-	CreatedAt   	time.Time      `json:"created_at"`
-	UpdatedAt   	time.Time      `json:"updated_at"`
-	DeletedAt   	gorm.DeletedAt `json:"deleted_at" gorm:"index"`
+	ID             int            `gorm:"primaryKey;autoIncrement"`
+	Name           string         `gorm:"size:100;not null"`
+	NameKey        string         `gorm:"size:100;index"` // lihat NameKey()
+	Slug           string         `gorm:"size:120;uniqueIndex"`
+	ShortName      string         `gorm:"size:50"`
+	Abbreviation   string         `gorm:"size:3"`
+	PrimaryColor   string         `gorm:"size:7"`
+	SecondaryColor string         `gorm:"size:7"`
+	Logo           string         `gorm:"size:255"`
+	YearFounded    int            `gorm:"not null"`
+	Address        string         `gorm:"size:255"`
+	City           string         `gorm:"size:100"`
+	HomeVenueID    *int           `gorm:"index"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `json:"deleted_at" gorm:"index"`
+
+	// Relasi
+	HomeVenue *venue.Venue  `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+	Versions  []TeamVersion `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+// TeamVersion menyimpan atribut tim yang berlaku mulai EffectiveFrom sampai
+// versi berikutnya, supaya pertandingan lama tetap menampilkan nama dan logo
+// saat pertandingan itu dimainkan.
+type TeamVersion struct {
+	ID             int       `gorm:"primaryKey;autoIncrement"`
+	TeamID         int       `gorm:"not null;index:idx_team_versions_effective,priority:1"`
+	EffectiveFrom  time.Time `gorm:"not null;index:idx_team_versions_effective,priority:2"`
+	Name           string    `gorm:"size:100;not null"`
+	ShortName      string    `gorm:"size:50"`
+	Abbreviation   string    `gorm:"size:3"`
+	PrimaryColor   string    `gorm:"size:7"`
+	SecondaryColor string    `gorm:"size:7"`
+	Logo           string    `gorm:"size:255"`
+	Address        string    `gorm:"size:255"`
+	City           string    `gorm:"size:100"`
+	HomeVenueID    *int
+	CreatedAt      time.Time
 }
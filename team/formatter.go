@@ -1,21 +1,127 @@
 package team
 
+import (
+	"time"
+
+	"footballteam/media"
+)
+
+// URLResolver mengubah storage key menjadi URL publik. Dipenuhi oleh
+// storage.Storage.
+type URLResolver interface {
+	URL(key string) string
+}
+
 type TeamFormatter struct {
-	ID          int    `json:"id"`
-	Name        string `json:"name"`
-	Logo        string `json:"logo"`
-	YearFounded int    `json:"year_founded"`
-	Address     string `json:"address"`
-	City        string `json:"city"`
+	ID             int    `json:"id"`
+	Name           string `json:"name"`
+	Slug           string `json:"slug"`
+	ShortName      string `json:"short_name"`
+	Abbreviation   string `json:"abbreviation"`
+	PrimaryColor   string `json:"primary_color"`
+	SecondaryColor string `json:"secondary_color"`
+	Logo           string `json:"logo"`
+	LogoMedium     string `json:"logo_medium"`
+	LogoThumbnail  string `json:"logo_thumbnail"`
+	YearFounded    int    `json:"year_founded"`
+	Address        string `json:"address"`
+	City           string `json:"city"`
+	HomeVenueID    *int   `json:"home_venue_id"`
 }
 
-func FormatTeam(team Team) TeamFormatter {
+func FormatTeam(team Team, urls URLResolver) TeamFormatter {
 	return TeamFormatter{
-		ID:          team.ID,
-		Name:        team.Name,
-		Logo:        team.Logo,
-		YearFounded: team.YearFounded,
-		Address:     team.Address,
-		City:        team.City,
+		ID:             team.ID,
+		Name:           team.Name,
+		Slug:           team.Slug,
+		ShortName:      team.ShortName,
+		Abbreviation:   team.Abbreviation,
+		PrimaryColor:   team.PrimaryColor,
+		SecondaryColor: team.SecondaryColor,
+		Logo:           LogoURL(team.Logo, media.VariantOriginal, urls),
+		LogoMedium:     LogoURL(team.Logo, media.VariantMedium, urls),
+		LogoThumbnail:  LogoURL(team.Logo, media.VariantThumbnail, urls),
+		YearFounded:    team.YearFounded,
+		Address:        team.Address,
+		City:           team.City,
+		HomeVenueID:    team.HomeVenueID,
+	}
+}
+
+func FormatTeams(teams []Team, urls URLResolver) []TeamFormatter {
+	formatted := []TeamFormatter{}
+	for _, t := range teams {
+		formatted = append(formatted, FormatTeam(t, urls))
+	}
+	return formatted
+}
+
+type TrashedTeamFormatter struct {
+	TeamFormatter
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+func FormatTrashedTeams(teams []Team, urls URLResolver) []TrashedTeamFormatter {
+	formatted := []TrashedTeamFormatter{}
+	for _, t := range teams {
+		formatted = append(formatted, TrashedTeamFormatter{FormatTeam(t, urls), t.DeletedAt.Time})
+	}
+	return formatted
+}
+
+// LogoURL mengembalikan string kosong untuk tim tanpa logo.
+func LogoURL(key string, variant media.Variant, urls URLResolver) string {
+	if key == "" {
+		return ""
+	}
+	return urls.URL(media.VariantPath(key, variant))
+}
+
+// TeamVersionFormatter adalah satu baris di GET /teams/:id/history.
+// EffectiveTo kosong untuk versi yang sedang berlaku, dan Changes berisi
+// field yang berubah dibanding versi sebelumnya (kosong untuk versi pertama).
+type TeamVersionFormatter struct {
+	EffectiveFrom  time.Time  `json:"effective_from"`
+	EffectiveTo    *time.Time `json:"effective_to"`
+	Name           string     `json:"name"`
+	ShortName      string     `json:"short_name"`
+	Abbreviation   string     `json:"abbreviation"`
+	PrimaryColor   string     `json:"primary_color"`
+	SecondaryColor string     `json:"secondary_color"`
+	Logo           string     `json:"logo"`
+	Address        string     `json:"address"`
+	City           string     `json:"city"`
+	HomeVenueID    *int       `json:"home_venue_id"`
+	Changes        []string   `json:"changes"`
+}
+
+// FormatTeamHistory menerima versi dari yang paling lama (urutan
+// FindVersions) dan mengembalikannya dari yang paling baru.
+func FormatTeamHistory(versions []TeamVersion, urls URLResolver) []TeamVersionFormatter {
+	formatted := []TeamVersionFormatter{}
+	for i := len(versions) - 1; i >= 0; i-- {
+		v := versions[i]
+		item := TeamVersionFormatter{
+			EffectiveFrom:  v.EffectiveFrom,
+			Name:           v.Name,
+			ShortName:      v.ShortName,
+			Abbreviation:   v.Abbreviation,
+			PrimaryColor:   v.PrimaryColor,
+			SecondaryColor: v.SecondaryColor,
+			Logo:           LogoURL(v.Logo, media.VariantOriginal, urls),
+			Address:        v.Address,
+			City:           v.City,
+			HomeVenueID:    v.HomeVenueID,
+			Changes:        []string{},
+		}
+		if i+1 < len(versions) {
+			effectiveTo := versions[i+1].EffectiveFrom
+			item.EffectiveTo = &effectiveTo
+		}
+		if i > 0 {
+			item.Changes = VersionChanges(versions[i-1], v)
+		}
+		formatted = append(formatted, item)
 	}
+	return formatted
 }
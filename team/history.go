@@ -0,0 +1,88 @@
+package team
+
+import "time"
+
+// Snapshot mengambil atribut tim yang diversikan, berlaku mulai
+// effectiveFrom.
+func (t Team) Snapshot(effectiveFrom time.Time) TeamVersion {
+	return TeamVersion{
+		TeamID:         t.ID,
+		EffectiveFrom:  effectiveFrom,
+		Name:           t.Name,
+		ShortName:      t.ShortName,
+		Abbreviation:   t.Abbreviation,
+		PrimaryColor:   t.PrimaryColor,
+		SecondaryColor: t.SecondaryColor,
+		Logo:           t.Logo,
+		Address:        t.Address,
+		City:           t.City,
+		HomeVenueID:    t.HomeVenueID,
+	}
+}
+
+// AsOf mengembalikan tim dengan atribut yang berlaku pada waktu at. Versions
+// harus sudah di-preload; tanpa versi tim dikembalikan apa adanya. Waktu
+// sebelum versi pertama memakai versi pertama.
+func (t Team) AsOf(at time.Time) Team {
+	if len(t.Versions) == 0 {
+		return t
+	}
+
+	current := -1
+	earliest := 0
+	for i, v := range t.Versions {
+		if v.EffectiveFrom.Before(t.Versions[earliest].EffectiveFrom) {
+			earliest = i
+		}
+		if v.EffectiveFrom.After(at) {
+			continue
+		}
+		if current == -1 || !v.EffectiveFrom.Before(t.Versions[current].EffectiveFrom) {
+			current = i
+		}
+	}
+	if current == -1 {
+		current = earliest
+	}
+
+	v := t.Versions[current]
+	t.Name = v.Name
+	t.ShortName = v.ShortName
+	t.Abbreviation = v.Abbreviation
+	t.PrimaryColor = v.PrimaryColor
+	t.SecondaryColor = v.SecondaryColor
+	t.Logo = v.Logo
+	t.Address = v.Address
+	t.City = v.City
+	t.HomeVenueID = v.HomeVenueID
+	return t
+}
+
+// VersionChanges mengembalikan nama field (sesuai JSON) yang berbeda antara
+// dua versi. Urutannya tetap supaya respon history stabil.
+func VersionChanges(before, after TeamVersion) []string {
+	changes := []string{}
+	add := func(field string, changed bool) {
+		if changed {
+			changes = append(changes, field)
+		}
+	}
+
+	add("name", before.Name != after.Name)
+	add("short_name", before.ShortName != after.ShortName)
+	add("abbreviation", before.Abbreviation != after.Abbreviation)
+	add("primary_color", before.PrimaryColor != after.PrimaryColor)
+	add("secondary_color", before.SecondaryColor != after.SecondaryColor)
+	add("logo", before.Logo != after.Logo)
+	add("address", before.Address != after.Address)
+	add("city", before.City != after.City)
+	add("home_venue_id", !sameID(before.HomeVenueID, after.HomeVenueID))
+	return changes
+}
+
+func sameID(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
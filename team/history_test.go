@@ -0,0 +1,106 @@
+package team
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func at(value string) time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestAsOf(t *testing.T) {
+	// Sengaja tidak berurutan; AsOf tidak boleh bergantung pada urutan preload
+	team := Team{
+		ID:   1,
+		Name: "Garuda United",
+		Versions: []TeamVersion{
+			{EffectiveFrom: at("2024-07-01T00:00:00Z"), Name: "Garuda United"},
+			{EffectiveFrom: at("2020-01-01T00:00:00Z"), Name: "Garuda FC"},
+			{EffectiveFrom: at("2022-03-15T12:00:00Z"), Name: "Garuda Jaya"},
+		},
+	}
+
+	tests := []struct {
+		at   string
+		want string
+	}{
+		{"2019-12-31T23:59:59Z", "Garuda FC"}, // sebelum versi pertama
+		{"2020-01-01T00:00:00Z", "Garuda FC"},
+		{"2022-03-15T11:59:59Z", "Garuda FC"},
+		{"2022-03-15T12:00:00Z", "Garuda Jaya"}, // tepat di batas versi
+		{"2024-06-30T23:59:59Z", "Garuda Jaya"},
+		{"2024-07-01T00:00:00Z", "Garuda United"},
+		{"2030-01-01T00:00:00Z", "Garuda United"},
+	}
+
+	for _, tt := range tests {
+		if got := team.AsOf(at(tt.at)).Name; got != tt.want {
+			t.Errorf("AsOf(%s).Name = %q, want %q", tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestAsOfWithoutVersions(t *testing.T) {
+	team := Team{ID: 1, Name: "Garuda FC", City: "Jakarta"}
+
+	if got := team.AsOf(at("2020-01-01T00:00:00Z")); !reflect.DeepEqual(got, team) {
+		t.Errorf("AsOf = %+v, want team unchanged", got)
+	}
+}
+
+func TestAsOfCopiesVersionedFields(t *testing.T) {
+	venueID := 3
+	team := Team{
+		ID:          1,
+		Name:        "Garuda FC",
+		Slug:        "garuda-fc",
+		HomeVenueID: &venueID,
+		Versions: []TeamVersion{
+			{EffectiveFrom: at("2020-01-01T00:00:00Z"), Name: "Persija Lama", ShortName: "Persija", Abbreviation: "PSJ", PrimaryColor: "#FF0000", Logo: "logo/old.png", City: "Bogor"},
+		},
+	}
+
+	got := team.AsOf(at("2021-01-01T00:00:00Z"))
+	if got.Name != "Persija Lama" || got.ShortName != "Persija" || got.Abbreviation != "PSJ" ||
+		got.PrimaryColor != "#FF0000" || got.Logo != "logo/old.png" || got.City != "Bogor" || got.HomeVenueID != nil {
+		t.Errorf("AsOf = %+v", got)
+	}
+	// Field yang tidak diversikan tetap dari data sekarang
+	if got.ID != 1 || got.Slug != "garuda-fc" {
+		t.Errorf("AsOf changed unversioned fields: %+v", got)
+	}
+	if team.Name != "Garuda FC" {
+		t.Errorf("AsOf modified the original team: %+v", team)
+	}
+}
+
+func TestVersionChanges(t *testing.T) {
+	one, two := 1, 2
+	before := TeamVersion{Name: "Garuda FC", City: "Jakarta", HomeVenueID: &one}
+
+	tests := []struct {
+		name  string
+		after TeamVersion
+		want  []string
+	}{
+		{"no change", TeamVersion{Name: "Garuda FC", City: "Jakarta", HomeVenueID: &one}, []string{}},
+		{"same venue id, different pointer", TeamVersion{Name: "Garuda FC", City: "Jakarta", HomeVenueID: func() *int { v := 1; return &v }()}, []string{}},
+		{"name and city", TeamVersion{Name: "Garuda United", City: "Bogor", HomeVenueID: &one}, []string{"name", "city"}},
+		{"venue changed", TeamVersion{Name: "Garuda FC", City: "Jakarta", HomeVenueID: &two}, []string{"home_venue_id"}},
+		{"venue removed", TeamVersion{Name: "Garuda FC", City: "Jakarta"}, []string{"home_venue_id"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VersionChanges(before, tt.after); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("VersionChanges = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
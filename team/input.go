@@ -1,15 +1,57 @@
 package team
 
+import (
+	"time"
+
+	"footballteam/helper"
+	"footballteam/user"
+)
+
 type CreateTeamInput struct {
-	Name        string `json:"name" binding:"required"`
-	YearFounded int    `json:"year_founded" binding:"required"`
-	Address     string `json:"address"`
-	City        string `json:"city"`
+	Name           string    `json:"name" binding:"required,max=100"`
+	ShortName      string    `json:"short_name" binding:"max=50"`
+	Abbreviation   string    `json:"abbreviation" binding:"omitempty,len=3,alpha"`
+	PrimaryColor   string    `json:"primary_color" binding:"omitempty,hexcolor"`
+	SecondaryColor string    `json:"secondary_color" binding:"omitempty,hexcolor"`
+	YearFounded    int       `json:"year_founded" binding:"required"`
+	Address        string    `json:"address"`
+	City           string    `json:"city"`
+	HomeVenueID    *int      `json:"home_venue_id"`
+	User           user.User `json:"-"`
 }
 
+// UpdateTeamInput: home_venue_id 0 menghapus kandang tim. EffectiveFrom
+// menandai sejak kapan perubahan nama, logo, warna, atau alamat berlaku
+// (default sekarang); boleh di masa lalu selama setelah perubahan terakhir.
 type UpdateTeamInput struct {
-	Name        string `json:"name"`
-	YearFounded int    `json:"year_founded"`
-	Address     string `json:"address"`
-	City        string `json:"city"`
+	Name           string     `json:"name" binding:"max=100"`
+	ShortName      string     `json:"short_name" binding:"max=50"`
+	Abbreviation   string     `json:"abbreviation" binding:"omitempty,len=3,alpha"`
+	PrimaryColor   string     `json:"primary_color" binding:"omitempty,hexcolor"`
+	SecondaryColor string     `json:"secondary_color" binding:"omitempty,hexcolor"`
+	YearFounded    int        `json:"year_founded"`
+	Address        string     `json:"address"`
+	City           string     `json:"city"`
+	HomeVenueID    *int       `json:"home_venue_id"`
+	EffectiveFrom  *time.Time `json:"effective_from"`
+	User           user.User  `json:"-"`
+}
+
+// DeleteTeamInput dibind dari query string DELETE /teams/:id. Policy kosong
+// berarti DeletePolicyRestrict.
+type DeleteTeamInput struct {
+	Policy     string    `form:"policy" binding:"omitempty,oneof=restrict cascade reassign"`
+	ReassignTo int       `form:"reassign_to" binding:"required_if=Policy reassign"`
+	User       user.User `form:"-"`
+}
+
+// ListTeamsInput dibind dari query string GET /teams. Sort diawali "-" untuk
+// urutan menurun, misalnya "-year_founded".
+type ListTeamsInput struct {
+	helper.PageInput
+	Query    string `form:"q"`
+	City     string `form:"city"`
+	YearFrom int    `form:"year_from" binding:"omitempty,min=1"`
+	YearTo   int    `form:"year_to" binding:"omitempty,min=1,gtefield=YearFrom"`
+	Sort     string `form:"sort" binding:"omitempty,oneof=name -name year_founded -year_founded created_at -created_at"`
 }
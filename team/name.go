@@ -0,0 +1,56 @@
+package team
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// stripAccents mengubah "Persebaya Surabaya Ñ" menjadi "Persebaya Surabaya N"
+// dengan memisahkan huruf dari tanda diakritiknya lalu membuang tandanya.
+func stripAccents(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	result, _, err := transform.String(t, s)
+	if err != nil {
+		return s
+	}
+	return result
+}
+
+// NameKey adalah bentuk nama yang dipakai untuk cek duplikasi: huruf kecil,
+// tanpa aksen, dan spasi dirapikan, jadi "garuda fc", "Garuda  FC", dan
+// "Garúda FC" dianggap sama.
+func NameKey(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(stripAccents(name))), " ")
+}
+
+// Slugify membentuk slug URL dari nama tim, misalnya "Garuda FC" menjadi
+// "garuda-fc". Slug yang hanya berisi angka diberi awalan "team-" supaya
+// tidak tertukar dengan ID di GET /teams/:id.
+func Slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range NameKey(name) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+
+	slug := strings.TrimSuffix(b.String(), "-")
+	if slug == "" {
+		return "team"
+	}
+	if strings.IndexFunc(slug, func(r rune) bool { return !unicode.IsDigit(r) }) == -1 {
+		return "team-" + slug
+	}
+	return slug
+}
@@ -1,16 +1,41 @@
 package team
 
-import "gorm.io/gorm"
+import (
+	"strings"
+	"time"
+
+	"footballteam/helper"
+	"footballteam/venue"
+
+	"gorm.io/gorm"
+)
 
 type Repository interface {
-	FindAll() ([]Team, error)
+	FindAll(input ListTeamsInput) ([]Team, int64, error)
 	FindByID(id int) (Team, error)
 	Create(team Team) (Team, error)
 	Update(team Team) (Team, error)
 	Delete(team Team) error
-	FindByName(name string) (Team, error)
+	FindBySlug(slug string) (Team, error)
+	FindByNameKey(nameKey string) (Team, error)
+	SlugExists(slug string, excludeID int) (bool, error)
+	UpdateWithVersion(team Team, version TeamVersion) (Team, error)
+	FindVersions(teamID int) ([]TeamVersion, error)
+	FindLatestVersion(teamID int) (TeamVersion, error)
+	CountByLogo(logo string) (int64, error)
+	VenueExists(venueID int) (bool, error)
+	FindReferences(teamID int, includeTrashed bool) (map[string][]int, error)
+	CascadeDelete(team Team) (map[string][]int, error)
+	ReassignAndDelete(team Team, targetID int) error
+	FindTrashed() ([]Team, error)
+	FindTrashedByID(id int) (Team, error)
+	Restore(team Team) (Team, error)
+	Purge(team Team) error
 }
 
+// transferTypeFree sama dengan player.TransferFree.
+const transferTypeFree = "free"
+
 type repository struct {
 	db *gorm.DB
 }
@@ -19,10 +44,43 @@ func NewRepository(db *gorm.DB) *repository {
 	return &repository{db}
 }
 
-func (r *repository) FindAll() ([]Team, error) {
+func (r *repository) FindAll(input ListTeamsInput) ([]Team, int64, error) {
 	var teams []Team
-	err := r.db.Find(&teams).Error
-	return teams, err
+	var total int64
+
+	query := r.db.Model(&Team{})
+	if input.Query != "" {
+		query = query.Where("name LIKE ?", helper.LikePattern(input.Query))
+	}
+	if input.City != "" {
+		query = query.Where("city = ?", input.City)
+	}
+	if input.YearFrom != 0 {
+		query = query.Where("year_founded >= ?", input.YearFrom)
+	}
+	if input.YearTo != 0 {
+		query = query.Where("year_founded <= ?", input.YearTo)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return teams, total, err
+	}
+
+	err := query.Order(teamOrder(input.Sort)).Order("id").
+		Limit(input.PerPage).Offset(input.Offset()).
+		Find(&teams).Error
+	return teams, total, err
+}
+
+// teamOrder hanya menerima kolom yang sudah divalidasi di ListTeamsInput
+func teamOrder(sort string) string {
+	if sort == "" {
+		return "name"
+	}
+	if strings.HasPrefix(sort, "-") {
+		return strings.TrimPrefix(sort, "-") + " desc"
+	}
+	return sort
 }
 
 func (r *repository) FindByID(id int) (Team, error) {
@@ -45,8 +103,267 @@ func (r *repository) Delete(team Team) error {
 	return r.db.Delete(&team).Error
 }
 
-func (r *repository) FindByName(name string) (Team, error) {
+func (r *repository) FindBySlug(slug string) (Team, error) {
+	var team Team
+	err := r.db.Where("slug = ?", slug).First(&team).Error
+	return team, err
+}
+
+// FindByNameKey hanya mencari tim aktif; tim di trash boleh punya nama yang
+// sama sampai di-restore.
+func (r *repository) FindByNameKey(nameKey string) (Team, error) {
+	var team Team
+	err := r.db.Where("name_key = ?", nameKey).First(&team).Error
+	return team, err
+}
+
+// SlugExists ikut memeriksa tim di trash karena slug punya unique index dan
+// tetap dipakai saat tim di-restore.
+func (r *repository) SlugExists(slug string, excludeID int) (bool, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&Team{}).Where("slug = ? AND id <> ?", slug, excludeID).Count(&count).Error
+	return count > 0, err
+}
+
+// FindTrashed mengambil data yang sudah di-soft delete, terbaru dulu.
+func (r *repository) FindTrashed() ([]Team, error) {
+	var teams []Team
+	err := r.db.Unscoped().Where("deleted_at IS NOT NULL").Order("deleted_at desc").Find(&teams).Error
+	return teams, err
+}
+
+func (r *repository) FindTrashedByID(id int) (Team, error) {
 	var team Team
-	err := r.db.Where("name = ?", name).First(&team).Error
+	err := r.db.Unscoped().Where("deleted_at IS NOT NULL").First(&team, id).Error
+	return team, err
+}
+
+func (r *repository) Restore(team Team) (Team, error) {
+	err := r.db.Unscoped().Model(&team).Update("deleted_at", nil).Error
 	return team, err
 }
+
+// Purge menghapus permanen, tidak bisa di-restore lagi.
+func (r *repository) Purge(team Team) error {
+	return r.db.Unscoped().Delete(&team).Error
+}
+
+// UpdateWithVersion menyimpan tim dan versi barunya dalam satu transaksi.
+func (r *repository) UpdateWithVersion(team Team, version TeamVersion) (Team, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Versions").Save(&team).Error; err != nil {
+			return err
+		}
+		version.TeamID = team.ID
+		return tx.Create(&version).Error
+	})
+	return team, err
+}
+
+// FindVersions diurutkan dari yang paling lama berlaku.
+func (r *repository) FindVersions(teamID int) ([]TeamVersion, error) {
+	var versions []TeamVersion
+	err := r.db.Where("team_id = ?", teamID).Order("effective_from").Order("id").Find(&versions).Error
+	return versions, err
+}
+
+func (r *repository) FindLatestVersion(teamID int) (TeamVersion, error) {
+	var version TeamVersion
+	err := r.db.Where("team_id = ?", teamID).Order("effective_from desc").Order("id desc").First(&version).Error
+	return version, err
+}
+
+// CountByLogo ikut menghitung tim di trash karena tim tersebut masih bisa
+// di-restore beserta logonya, dan versi lama karena pertandingan lama masih
+// menampilkan logo tersebut.
+func (r *repository) CountByLogo(logo string) (int64, error) {
+	var teams, versions int64
+	if err := r.db.Unscoped().Model(&Team{}).Where("logo = ?", logo).Count(&teams).Error; err != nil {
+		return 0, err
+	}
+	if err := r.db.Model(&TeamVersion{}).Where("logo = ?", logo).Count(&versions).Error; err != nil {
+		return 0, err
+	}
+	return teams + versions, nil
+}
+
+func (r *repository) VenueExists(venueID int) (bool, error) {
+	var count int64
+	err := r.db.Model(&venue.Venue{}).Where("id = ?", venueID).Count(&count).Error
+	return count > 0, err
+}
+
+// Relasi ke tim dicek lewat nama tabel langsung karena package player, staff,
+// match, dan match_result sudah bergantung ke package team.
+
+// FindReferences mengembalikan ID data yang masih menunjuk ke tim. Dengan
+// includeTrashed, data yang di-soft delete ikut dihitung (dipakai sebelum
+// purge karena foreign key tetap berlaku untuk baris tersebut).
+func (r *repository) FindReferences(teamID int, includeTrashed bool) (map[string][]int, error) {
+	references := map[string][]int{}
+
+	queries := map[string]*gorm.DB{
+		"players": r.db.Table("players").Where("team_id = ?", teamID),
+		"staff":   r.db.Table("staff").Where("team_id = ?", teamID),
+		"matches": r.db.Table("matches").Where("home_team_id = ? OR away_team_id = ?", teamID, teamID),
+	}
+	if includeTrashed {
+		queries["goals"] = r.db.Table("goals").Where("team_id = ?", teamID)
+		queries["transfers"] = r.db.Table("transfers").Where("from_team_id = ? OR to_team_id = ?", teamID, teamID)
+	}
+
+	for kind, query := range queries {
+		if !includeTrashed {
+			query = query.Where("deleted_at IS NULL")
+		}
+
+		var ids []int
+		if err := query.Order("id").Pluck("id", &ids).Error; err != nil {
+			return nil, err
+		}
+		references[kind] = ids
+	}
+
+	return references, nil
+}
+
+// conflictingNumbers mengembalikan ID pemain tim asal yang nomor punggungnya
+// sudah dipakai di tim tujuan.
+func conflictingNumbers(db *gorm.DB, teamID int, targetID int) ([]int, error) {
+	var ids []int
+	err := db.Table("players").
+		Where("team_id = ? AND deleted_at IS NULL", teamID).
+		Where("number IN (?)", db.Table("players").Select("number").Where("team_id = ? AND deleted_at IS NULL", targetID)).
+		Order("id").
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// movableMatches mengembalikan ID pertandingan aktif tim yang belum punya
+// hasil (termasuk hasil di trash). Pertandingan yang sudah dimainkan tetap
+// tercatat atas tim lama supaya history tidak berubah.
+func movableMatches(db *gorm.DB, teamID int) *gorm.DB {
+	return db.Table("matches").
+		Where("(home_team_id = ? OR away_team_id = ?) AND deleted_at IS NULL", teamID, teamID).
+		Where("NOT EXISTS (SELECT 1 FROM match_results WHERE match_results.match_id = matches.id)")
+}
+
+// CascadeDelete men-soft delete tim beserta pemain, staf, pertandingan, hasil
+// pertandingan, dan gol-nya dalam satu transaksi. Yang dikembalikan adalah ID
+// data yang ikut terhapus per jenis entity audit (player, staff, match,
+// match_result, goal).
+func (r *repository) CascadeDelete(team Team) (map[string][]int, error) {
+	now := time.Now()
+	deleted := map[string][]int{}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var matchIDs []int
+		if err := tx.Table("matches").
+			Where("(home_team_id = ? OR away_team_id = ?) AND deleted_at IS NULL", team.ID, team.ID).
+			Order("id").
+			Pluck("id", &matchIDs).Error; err != nil {
+			return err
+		}
+
+		var resultIDs []int
+		if err := tx.Table("match_results").
+			Where("match_id IN ? AND deleted_at IS NULL", matchIDs).
+			Order("id").
+			Pluck("id", &resultIDs).Error; err != nil {
+			return err
+		}
+
+		steps := []struct {
+			entityType string
+			table      string
+			query      *gorm.DB
+		}{
+			{"goal", "goals", tx.Table("goals").Where("match_result_id IN ?", resultIDs)},
+			{"match_result", "match_results", tx.Table("match_results").Where("id IN ?", resultIDs)},
+			{"match", "matches", tx.Table("matches").Where("id IN ?", matchIDs)},
+			{"player", "players", tx.Table("players").Where("team_id = ?", team.ID)},
+			{"staff", "staff", tx.Table("staff").Where("team_id = ?", team.ID)},
+		}
+		for _, step := range steps {
+			var ids []int
+			if err := step.query.Where("deleted_at IS NULL").Order("id").Pluck("id", &ids).Error; err != nil {
+				return err
+			}
+			if len(ids) == 0 {
+				continue
+			}
+			if err := tx.Table(step.table).Where("id IN ?", ids).Update("deleted_at", now).Error; err != nil {
+				return err
+			}
+			deleted[step.entityType] = ids
+		}
+
+		return tx.Delete(&team).Error
+	})
+
+	return deleted, err
+}
+
+// ReassignAndDelete memindahkan pemain, staf, dan pertandingan yang belum
+// dimainkan ke tim tujuan (misalnya saat dua klub merger), lalu men-soft
+// delete tim asal. Pertandingan yang sudah punya hasil beserta gol-nya tetap
+// atas tim lama. Setiap pemain yang dipindah mendapat baris transfer supaya
+// history-nya tidak melompati merger.
+//
+// Nomor punggung yang bentrok di tim tujuan dan pertandingan melawan tim
+// tujuan dicek di dalam transaksi dan dikembalikan sebagai *helper.InUseError.
+func (r *repository) ReassignAndDelete(team Team, targetID int) error {
+	now := time.Now()
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		conflicts, err := conflictingNumbers(tx, team.ID, targetID)
+		if err != nil {
+			return err
+		}
+
+		var matchIDs []int
+		if err := movableMatches(tx, team.ID).Order("id").Pluck("id", &matchIDs).Error; err != nil {
+			return err
+		}
+
+		// Pertandingan melawan tim tujuan akan menjadi tim melawan dirinya sendiri
+		var selfMatches []int
+		if err := tx.Table("matches").
+			Where("id IN ? AND (home_team_id = ? OR away_team_id = ?)", matchIDs, targetID, targetID).
+			Order("id").
+			Pluck("id", &selfMatches).Error; err != nil {
+			return err
+		}
+
+		if err := helper.NewInUseError(map[string][]int{"players": conflicts, "matches": selfMatches}); err != nil {
+			return err
+		}
+
+		// Tabel transfers milik package player (lihat player.TransferFree);
+		// package ini tidak bisa meng-import player
+		err = tx.Exec(`INSERT INTO transfers (player_id, from_team_id, to_team_id, date, type, number, created_at)
+			SELECT id, ?, ?, ?, ?, number, ? FROM players WHERE team_id = ? AND deleted_at IS NULL`,
+			team.ID, targetID, now.Format("2006-01-02"), transferTypeFree, now, team.ID).Error
+		if err != nil {
+			return err
+		}
+
+		updates := []struct {
+			query  *gorm.DB
+			column string
+		}{
+			{tx.Table("players").Where("deleted_at IS NULL"), "team_id"},
+			{tx.Table("staff").Where("deleted_at IS NULL"), "team_id"},
+			{tx.Table("matches").Where("id IN ?", matchIDs), "home_team_id"},
+			{tx.Table("matches").Where("id IN ?", matchIDs), "away_team_id"},
+		}
+
+		for _, u := range updates {
+			if err := u.query.Where(u.column+" = ?", team.ID).Update(u.column, targetID).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Delete(&team).Error
+	})
+}
@@ -1,68 +1,145 @@
 package team
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"log"
+	"strings"
 	"time"
+
+	"footballteam/audit"
+	"footballteam/helper"
+	"footballteam/media"
+	"footballteam/user"
+
+	"gorm.io/gorm"
+)
+
+const auditEntityType = "team"
+
+// Kebijakan DELETE /teams/:id terhadap pemain dan pertandingan yang masih
+// menunjuk ke tim.
+const (
+	DeletePolicyRestrict = "restrict"
+	DeletePolicyCascade  = "cascade"
+	DeletePolicyReassign = "reassign"
+)
+
+var (
+	ErrNameTaken            = errors.New("another team already uses this name")
+	ErrInvalidDeletePolicy  = errors.New("delete policy must be restrict, cascade or reassign")
+	ErrReassignTarget       = errors.New("reassign target must be another existing team")
+	ErrVenueNotFound        = errors.New("venue not found")
+	ErrInvalidEffectiveDate = errors.New("effective_from must not be in the future or before the latest change")
 )
 
 type Service interface {
-	GetAllTeams() ([]Team, error)
+	GetAllTeams(input ListTeamsInput) ([]Team, int64, error)
 	GetTeamByID(id int) (Team, error)
+	GetTeamBySlug(slug string) (Team, error)
+	GetTeamHistory(id int) ([]TeamVersion, error)
 	CreateTeam(input CreateTeamInput) (Team, error)
 	UpdateTeam(id int, input UpdateTeamInput) (Team, error)
-	DeleteTeam(id int) error
-	SaveLogo(id int, fileLocation string) (Team, error)
+	DeleteTeam(id int, input DeleteTeamInput) error
+	SaveLogo(id int, file io.Reader, currentUser user.User) (Team, error)
+	GetTrashedTeams() ([]Team, error)
+	RestoreTeam(id int, currentUser user.User) (Team, error)
+	PurgeTeam(id int, currentUser user.User) error
 }
 
 type service struct {
-	repository Repository
+	repository   Repository
+	auditService audit.Service
+	mediaService media.Service
 }
 
-func NewService(repository Repository) *service {
-	return &service{repository}
+func NewService(repository Repository, auditService audit.Service, mediaService media.Service) *service {
+	return &service{repository, auditService, mediaService}
 }
 
-func (s *service) GetAllTeams() ([]Team, error) {
-	return s.repository.FindAll()
+func (s *service) GetAllTeams(input ListTeamsInput) ([]Team, int64, error) {
+	input.Normalize()
+	return s.repository.FindAll(input)
 }
 
 func (s *service) GetTeamByID(id int) (Team, error) {
 	return s.repository.FindByID(id)
 }
 
+func (s *service) GetTeamBySlug(slug string) (Team, error) {
+	return s.repository.FindBySlug(slug)
+}
+
 func (s *service) CreateTeam(input CreateTeamInput) (Team, error) {
-	// Cek apakah nama tim sudah ada
-	existing, err := s.repository.FindByName(input.Name)
-	if err == nil {
-		return existing, fmt.Errorf("team with name '%s' already exists", input.Name)
+	nameKey := NameKey(input.Name)
+	if err := s.checkNameAvailable(nameKey, 0); err != nil {
+		return Team{}, err
+	}
+
+	homeVenueID, err := s.homeVenueID(input.HomeVenueID)
+	if err != nil {
+		return Team{}, err
 	}
 
 	team := Team{
-		Name:        input.Name,
-		YearFounded: input.YearFounded,
-		Address:     input.Address,
-		City:        input.City,
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
+		Name:           input.Name,
+		NameKey:        nameKey,
+		ShortName:      input.ShortName,
+		Abbreviation:   strings.ToUpper(input.Abbreviation),
+		PrimaryColor:   strings.ToUpper(input.PrimaryColor),
+		SecondaryColor: strings.ToUpper(input.SecondaryColor),
+		YearFounded:    input.YearFounded,
+		Address:        input.Address,
+		City:           input.City,
+		HomeVenueID:    homeVenueID,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+
+	// Versi pertama dibuat bersama tim lewat relasi Versions
+	team.Versions = []TeamVersion{team.Snapshot(team.CreatedAt)}
+
+	newTeam, err := s.saveWithSlug(team, s.repository.Create)
+	if err != nil {
+		return newTeam, err
 	}
 
-	return s.repository.Create(team)
+	s.auditService.Record(auditActor(input.User), audit.ActionCreate, auditEntityType, newTeam.ID, nil, newTeam)
+
+	return newTeam, nil
 }
 
 func (s *service) UpdateTeam(id int, input UpdateTeamInput) (Team, error) {
-	// Cek apakah nama tim sudah ada
-	existing, err := s.repository.FindByName(input.Name)
-	if err == nil {
-		return existing, fmt.Errorf("team with name '%s' already exists", input.Name)
-	}
-	
 	team, err := s.repository.FindByID(id)
 	if err != nil {
 		return team, err
 	}
+	before := team
 
+	renamed := false
 	if input.Name != "" {
+		nameKey := NameKey(input.Name)
+		if nameKey != team.NameKey {
+			if err := s.checkNameAvailable(nameKey, team.ID); err != nil {
+				return team, err
+			}
+			renamed = true
+		}
 		team.Name = input.Name
+		team.NameKey = nameKey
+	}
+	if input.ShortName != "" {
+		team.ShortName = input.ShortName
+	}
+	if input.Abbreviation != "" {
+		team.Abbreviation = strings.ToUpper(input.Abbreviation)
+	}
+	if input.PrimaryColor != "" {
+		team.PrimaryColor = strings.ToUpper(input.PrimaryColor)
+	}
+	if input.SecondaryColor != "" {
+		team.SecondaryColor = strings.ToUpper(input.SecondaryColor)
 	}
 	if input.YearFounded != 0 {
 		team.YearFounded = input.YearFounded
@@ -73,33 +150,334 @@ func (s *service) UpdateTeam(id int, input UpdateTeamInput) (Team, error) {
 	if input.City != "" {
 		team.City = input.City
 	}
+	if input.HomeVenueID != nil {
+		homeVenueID, err := s.homeVenueID(input.HomeVenueID)
+		if err != nil {
+			return team, err
+		}
+		team.HomeVenueID = homeVenueID
+	}
+
+	save := s.repository.Update
+	if changes := VersionChanges(before.Snapshot(time.Time{}), team.Snapshot(time.Time{})); len(changes) > 0 {
+		effectiveFrom, err := s.effectiveFrom(team.ID, input.EffectiveFrom)
+		if err != nil {
+			return team, err
+		}
+		save = func(t Team) (Team, error) {
+			return s.repository.UpdateWithVersion(t, t.Snapshot(effectiveFrom))
+		}
+	}
+
+	var updatedTeam Team
+	if renamed {
+		// Slug hanya dibuat ulang jika nama benar-benar berubah, bukan sekadar
+		// huruf besar/kecil, supaya URL lama tidak putus tanpa alasan
+		updatedTeam, err = s.saveWithSlug(team, save)
+	} else {
+		updatedTeam, err = save(team)
+	}
+	if err != nil {
+		return updatedTeam, err
+	}
+
+	s.auditService.Record(auditActor(input.User), audit.ActionUpdate, auditEntityType, updatedTeam.ID, before, updatedTeam)
 
-	return s.repository.Update(team)
+	return updatedTeam, nil
 }
 
-func (s *service) DeleteTeam(id int) error {
+// DeleteTeam menerapkan input.Policy:
+//   - restrict: gagal dengan *helper.InUseError jika masih ada pemain, staf,
+//     atau pertandingan aktif.
+//   - cascade: pemain, staf, pertandingan, hasil pertandingan, dan gol ikut
+//     di-soft delete dan masing-masing dicatat di audit log. RestoreTeam tidak
+//     ikut me-restore data tersebut.
+//   - reassign: pemain, staf, dan pertandingan yang belum dimainkan dipindahkan
+//     ke tim input.ReassignTo dan setiap pemain mendapat catatan transfer.
+//     Pemain yang nomor punggungnya bentrok dan pertandingan melawan tim
+//     tujuan dilaporkan sebagai blocker.
+func (s *service) DeleteTeam(id int, input DeleteTeamInput) error {
 	team, err := s.repository.FindByID(id)
 	if err != nil {
 		return err
 	}
-	return s.repository.Delete(team)
+
+	switch input.Policy {
+	case "", DeletePolicyRestrict:
+		references, err := s.repository.FindReferences(team.ID, false)
+		if err != nil {
+			return err
+		}
+		if err := helper.NewInUseError(references); err != nil {
+			return err
+		}
+		if err := s.repository.Delete(team); err != nil {
+			return err
+		}
+	case DeletePolicyCascade:
+		deleted, err := s.repository.CascadeDelete(team)
+		if err != nil {
+			return err
+		}
+		// Data yang ikut terhapus dicatat satu per satu supaya terlihat di
+		// audit log masing-masing entity
+		for entityType, ids := range deleted {
+			for _, childID := range ids {
+				s.auditService.Record(auditActor(input.User), audit.ActionDelete, entityType, childID, nil, nil)
+			}
+		}
+	case DeletePolicyReassign:
+		if input.ReassignTo == team.ID {
+			return ErrReassignTarget
+		}
+		if _, err := s.repository.FindByID(input.ReassignTo); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrReassignTarget
+			}
+			return err
+		}
+
+		if err := s.repository.ReassignAndDelete(team, input.ReassignTo); err != nil {
+			return err
+		}
+	default:
+		return ErrInvalidDeletePolicy
+	}
+
+	s.auditService.Record(auditActor(input.User), audit.ActionDelete, auditEntityType, team.ID, team, nil)
+
+	return nil
 }
 
-func (s *service) SaveLogo(id int, fileLocation string) (Team, error) {
-	fmt.Println("🔍 SaveLogo() called with ID:", id)
+// SaveLogo memproses gambar lewat media service lalu menghapus file logo lama
+// jika sudah tidak dipakai tim lain.
+func (s *service) SaveLogo(id int, file io.Reader, currentUser user.User) (Team, error) {
+	if !currentUser.CanManageTeam(id) {
+		return Team{}, user.ErrTeamAccessDenied
+	}
+
 	team, err := s.repository.FindByID(id)
-	fmt.Println("🔍 Team result:", team, "Error:", err)
 	if err != nil {
 		return team, err
 	}
 
-	team.Logo = fileLocation
+	logo, err := s.mediaService.SaveImage(file)
+	if err != nil {
+		return team, err
+	}
+
+	before := team
+	team.Logo = logo
 
-	updateTeam, err := s.repository.Update(team)
+	var updateTeam Team
+	if logo == before.Logo {
+		updateTeam, err = s.repository.Update(team)
+	} else {
+		updateTeam, err = s.repository.UpdateWithVersion(team, team.Snapshot(time.Now()))
+	}
 
 	if err != nil {
+		s.removeUnusedLogo(logo)
 		return updateTeam, err
 	}
 
+	if before.Logo != logo {
+		s.removeUnusedLogo(before.Logo)
+	}
+
+	s.auditService.Record(auditActor(currentUser), audit.ActionUpdate, auditEntityType, updateTeam.ID, before, updateTeam)
+
 	return updateTeam, nil
 }
+
+// removeUnusedLogo tidak mengembalikan error karena perubahan data sudah
+// tersimpan; file yang gagal dihapus hanya dicatat di log.
+func (s *service) removeUnusedLogo(logo string) {
+	if logo == "" {
+		return
+	}
+
+	count, err := s.repository.CountByLogo(logo)
+	if err != nil {
+		log.Printf("team: failed to check usage of logo %s: %v", logo, err)
+		return
+	}
+	if count > 0 {
+		return
+	}
+
+	if err := s.mediaService.Remove(logo); err != nil {
+		log.Printf("team: failed to remove logo %s: %v", logo, err)
+	}
+}
+
+// GetTeamHistory mengembalikan versi atribut tim, dari yang paling lama.
+func (s *service) GetTeamHistory(id int) ([]TeamVersion, error) {
+	team, err := s.repository.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.repository.FindVersions(team.ID)
+}
+
+// effectiveFrom memvalidasi tanggal berlaku perubahan. Tanggal tidak boleh
+// di masa depan (atribut tim langsung berubah saat disimpan) dan harus
+// setelah versi terakhir supaya urutan history tidak tumpang tindih.
+func (s *service) effectiveFrom(teamID int, requested *time.Time) (time.Time, error) {
+	now := time.Now()
+	if requested == nil {
+		return now, nil
+	}
+	if requested.After(now) {
+		return time.Time{}, ErrInvalidEffectiveDate
+	}
+
+	latest, err := s.repository.FindLatestVersion(teamID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return time.Time{}, err
+	}
+	if err == nil && !requested.After(latest.EffectiveFrom) {
+		return time.Time{}, ErrInvalidEffectiveDate
+	}
+
+	return *requested, nil
+}
+
+func (s *service) GetTrashedTeams() ([]Team, error) {
+	return s.repository.FindTrashed()
+}
+
+// RestoreTeam gagal jika nama tim sudah dipakai tim lain sejak dihapus.
+func (s *service) RestoreTeam(id int, currentUser user.User) (Team, error) {
+	team, err := s.repository.FindTrashedByID(id)
+	if err != nil {
+		return team, err
+	}
+
+	if err := s.checkNameAvailable(NameKey(team.Name), team.ID); err != nil {
+		return team, err
+	}
+
+	restored, err := s.repository.Restore(team)
+	if err != nil {
+		return restored, err
+	}
+
+	s.auditService.Record(auditActor(currentUser), audit.ActionRestore, auditEntityType, restored.ID, nil, nil)
+
+	return restored, nil
+}
+
+// PurgeTeam hanya berlaku untuk tim yang sudah ada di trash. Pemain,
+// pertandingan, dan gol yang ikut terhapus tetap dihitung karena foreign key
+// berlaku juga untuk baris tersebut.
+func (s *service) PurgeTeam(id int, currentUser user.User) error {
+	team, err := s.repository.FindTrashedByID(id)
+	if err != nil {
+		return err
+	}
+
+	references, err := s.repository.FindReferences(team.ID, true)
+	if err != nil {
+		return err
+	}
+	if err := helper.NewInUseError(references); err != nil {
+		return err
+	}
+
+	// Versi ikut terhapus lewat foreign key, jadi logo lamanya dikumpulkan
+	// lebih dulu
+	versions, err := s.repository.FindVersions(team.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repository.Purge(team); err != nil {
+		return err
+	}
+
+	logos := map[string]bool{team.Logo: true}
+	for _, v := range versions {
+		logos[v.Logo] = true
+	}
+	for logo := range logos {
+		s.removeUnusedLogo(logo)
+	}
+
+	s.auditService.Record(auditActor(currentUser), audit.ActionPurge, auditEntityType, team.ID, team, nil)
+
+	return nil
+}
+
+// checkNameAvailable gagal dengan ErrNameTaken jika tim aktif lain (selain
+// excludeID) sudah memakai nama yang sama setelah dinormalisasi.
+func (s *service) checkNameAvailable(nameKey string, excludeID int) error {
+	existing, err := s.repository.FindByNameKey(nameKey)
+	if err == nil && existing.ID != excludeID {
+		return ErrNameTaken
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return nil
+}
+
+// maxSlugAttempts membatasi percobaan ulang jika dua request membuat slug
+// yang sama bersamaan dan salah satunya ditolak unique index.
+const maxSlugAttempts = 3
+
+// saveWithSlug mengisi slug unik dari nama tim lalu menyimpan lewat save.
+func (s *service) saveWithSlug(team Team, save func(Team) (Team, error)) (Team, error) {
+	var err error
+	for attempt := 0; attempt < maxSlugAttempts; attempt++ {
+		team.Slug, err = s.uniqueSlug(team.Name, team.ID)
+		if err != nil {
+			return team, err
+		}
+
+		var saved Team
+		saved, err = save(team)
+		if !errors.Is(err, gorm.ErrDuplicatedKey) {
+			return saved, err
+		}
+	}
+	return team, err
+}
+
+// uniqueSlug menambahkan akhiran -2, -3, dan seterusnya jika slug dasar sudah
+// dipakai tim lain, termasuk tim di trash.
+func (s *service) uniqueSlug(name string, excludeID int) (string, error) {
+	base := Slugify(name)
+	slug := base
+	for i := 2; ; i++ {
+		exists, err := s.repository.SlugExists(slug, excludeID)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return slug, nil
+		}
+		slug = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+// homeVenueID memvalidasi venue kandang. Nil atau 0 berarti tanpa kandang.
+func (s *service) homeVenueID(venueID *int) (*int, error) {
+	if venueID == nil || *venueID == 0 {
+		return nil, nil
+	}
+
+	exists, err := s.repository.VenueExists(*venueID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrVenueNotFound
+	}
+
+	return venueID, nil
+}
+
+func auditActor(currentUser user.User) audit.Actor {
+	return audit.UserActor(currentUser.ID, currentUser.Name)
+}
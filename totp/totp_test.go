@@ -0,0 +1,111 @@
+package totp
+
+import (
+	"testing"
+	"time"
+)
+
+// rfcSecret adalah seed SHA1 dari RFC 6238 Appendix B ("12345678901234567890")
+// dalam base32.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// Vektor RFC 6238 Appendix B memakai 8 digit; kode 6 digit adalah 6 digit
+// terakhirnya.
+func TestCodeAtRFC6238(t *testing.T) {
+	tests := []struct {
+		unix    int64
+		counter int64
+		rfcCode string
+	}{
+		{59, 0x1, "94287082"},
+		{1111111109, 0x23523EC, "07081804"},
+		{1111111111, 0x23523ED, "14050471"},
+		{1234567890, 0x273EF07, "89005924"},
+		{2000000000, 0x3F940AA, "69279037"},
+		{20000000000, 0x27BC86AA, "65353130"},
+	}
+
+	for _, tt := range tests {
+		counter := Counter(time.Unix(tt.unix, 0))
+		if counter != tt.counter {
+			t.Errorf("Counter(%d) = %X, want %X", tt.unix, counter, tt.counter)
+		}
+
+		code, err := CodeAt(rfcSecret, counter)
+		if err != nil {
+			t.Fatalf("CodeAt(%d): %v", counter, err)
+		}
+		if want := tt.rfcCode[len(tt.rfcCode)-Digits:]; code != want {
+			t.Errorf("CodeAt at %d = %s, want %s", tt.unix, code, want)
+		}
+	}
+}
+
+func TestCodeAtNormalizesSecret(t *testing.T) {
+	code, err := CodeAt(" gezdgnbvgy3tqojqgezdgnbvgy3tqojq ", 1)
+	if err != nil || code != "287082" {
+		t.Errorf("CodeAt = %q, %v, want 287082", code, err)
+	}
+	if _, err := CodeAt("not base32!", 1); err == nil {
+		t.Error("CodeAt with invalid secret: want error")
+	}
+}
+
+func TestValidateSkewWindow(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := Counter(now)
+
+	tests := []struct {
+		name        string
+		counter     int64
+		lastCounter int64
+		ok          bool
+	}{
+		{"current step", current, 0, true},
+		{"one step behind", current - 1, 0, true},
+		{"one step ahead", current + 1, 0, true},
+		{"two steps behind", current - 2, 0, false},
+		{"two steps ahead", current + 2, 0, false},
+		{"already used", current, current, false},
+		{"older than last used", current - 1, current, false},
+		{"newer than last used", current + 1, current, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := CodeAt(rfcSecret, tt.counter)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			matched, ok := Validate(rfcSecret, code, now, tt.lastCounter)
+			if ok != tt.ok {
+				t.Fatalf("Validate ok = %v, want %v", ok, tt.ok)
+			}
+			if ok && matched != tt.counter {
+				t.Errorf("Validate counter = %d, want %d", matched, tt.counter)
+			}
+		})
+	}
+}
+
+func TestValidateCodeFormat(t *testing.T) {
+	now := time.Unix(59, 0)
+
+	tests := []struct {
+		code string
+		ok   bool
+	}{
+		{"287082", true},
+		{" 287 082 ", true},
+		{"28708", false},
+		{"94287082", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if _, ok := Validate(rfcSecret, tt.code, now, 0); ok != tt.ok {
+			t.Errorf("Validate(%q) ok = %v, want %v", tt.code, ok, tt.ok)
+		}
+	}
+}
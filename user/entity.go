@@ -3,10 +3,43 @@ package user
 import "time"
 
 type User struct {
-	ID           int      `gorm:"primaryKey;autoIncrement"`
-	Name         string    `gorm:"size:100;not null"`
-	Email        string    `gorm:"size:100;unique;not null"`
-	PasswordHash string    `gorm:"size:255;not null"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID            int     `gorm:"primaryKey;autoIncrement"`
+	Name          string  `gorm:"size:100;not null"`
+	Email         string  `gorm:"size:100;unique;not null"`
+	PasswordHash  string  `gorm:"size:255;not null"`
+	Role          string  `gorm:"size:20;not null;default:viewer"`
+	TeamID        *int    `gorm:"index"`                // klub yang dikelola oleh role manager
+	OIDCSubject   *string `gorm:"size:255;uniqueIndex"` // claim "sub" dari identity provider
+	DeactivatedAt *time.Time
+	// TOTPSecret terisi sejak enrollment dimulai, TOTPEnabled baru true
+	// setelah kode pertama dikonfirmasi
+	TOTPSecret      string    `gorm:"size:64"`
+	TOTPEnabled     bool      `gorm:"not null;default:false"`
+	TOTPRequired    bool      `gorm:"not null;default:false"`
+	TOTPLastCounter int64     `gorm:"not null;default:0"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+func (u User) IsActive() bool {
+	return u.DeactivatedAt == nil
+}
+
+// PasswordResetToken hanya menyimpan hash token; token asli dikirim lewat email.
+type PasswordResetToken struct {
+	ID        int       `gorm:"primaryKey;autoIncrement"`
+	UserID    int       `gorm:"not null;index"`
+	TokenHash string    `gorm:"size:64;uniqueIndex;not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// RecoveryCode adalah kode cadangan sekali pakai untuk login 2FA.
+type RecoveryCode struct {
+	ID        int    `gorm:"primaryKey;autoIncrement"`
+	UserID    int    `gorm:"not null;index"`
+	CodeHash  string `gorm:"size:64;not null;index"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
 }
@@ -1,19 +1,105 @@
 package user
 
+import "time"
+
 type UserFormatter struct {
-	ID    int    `json:"id"`
-	Name  string `json:"name"`
-	Email string `json:"email"`
-	Token string `json:"token"`
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	Email        string `json:"email"`
+	Role         string `json:"role"`
+	TeamID       *int   `json:"team_id"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+	// RecoveryCodes hanya terisi saat login yang sekaligus menyelesaikan
+	// enrollment 2FA
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
 }
 
-func FormatUser(user User, token string) UserFormatter {
+func FormatUser(user User, token string, refreshToken string, expiresIn time.Duration) UserFormatter {
 	formatter := UserFormatter{
-		ID:    user.ID,
-		Name:  user.Name,
-		Email: user.Email,
-		Token: token,
+		ID:           user.ID,
+		Name:         user.Name,
+		Email:        user.Email,
+		Role:         user.Role,
+		TeamID:       user.TeamID,
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(expiresIn.Seconds()),
 	}
 
 	return formatter
 }
+
+type AccountFormatter struct {
+	ID                int        `json:"id"`
+	Name              string     `json:"name"`
+	Email             string     `json:"email"`
+	Role              string     `json:"role"`
+	TeamID            *int       `json:"team_id"`
+	Active            bool       `json:"active"`
+	TwoFactorEnabled  bool       `json:"two_factor_enabled"`
+	TwoFactorRequired bool       `json:"two_factor_required"`
+	DeactivatedAt     *time.Time `json:"deactivated_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+	TemporaryPassword string     `json:"temporary_password,omitempty"`
+}
+
+func FormatAccount(user User) AccountFormatter {
+	return AccountFormatter{
+		ID:                user.ID,
+		Name:              user.Name,
+		Email:             user.Email,
+		Role:              user.Role,
+		TeamID:            user.TeamID,
+		Active:            user.IsActive(),
+		TwoFactorEnabled:  user.TOTPEnabled,
+		TwoFactorRequired: user.TOTPRequired,
+		DeactivatedAt:     user.DeactivatedAt,
+		CreatedAt:         user.CreatedAt,
+	}
+}
+
+func FormatAccounts(users []User) []AccountFormatter {
+	formatted := []AccountFormatter{}
+	for _, u := range users {
+		formatted = append(formatted, FormatAccount(u))
+	}
+	return formatted
+}
+
+// TwoFactorChallengeFormatter dikirim sebagai respon POST /sessions jika
+// login butuh langkah kedua. Step bernilai "verify" atau "enroll".
+type TwoFactorChallengeFormatter struct {
+	TwoFactor      string `json:"two_factor"`
+	ChallengeToken string `json:"challenge_token"`
+	ExpiresIn      int64  `json:"expires_in"`
+}
+
+func FormatTwoFactorChallenge(step string, challengeToken string, expiresIn time.Duration) TwoFactorChallengeFormatter {
+	return TwoFactorChallengeFormatter{
+		TwoFactor:      step,
+		ChallengeToken: challengeToken,
+		ExpiresIn:      int64(expiresIn.Seconds()),
+	}
+}
+
+type TwoFactorEnrollmentFormatter struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+func FormatTwoFactorEnrollment(secret string, provisioningURI string) TwoFactorEnrollmentFormatter {
+	return TwoFactorEnrollmentFormatter{
+		Secret:          secret,
+		ProvisioningURI: provisioningURI,
+	}
+}
+
+type RecoveryCodesFormatter struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+func FormatRecoveryCodes(codes []string) RecoveryCodesFormatter {
+	return RecoveryCodesFormatter{RecoveryCodes: codes}
+}
@@ -4,3 +4,60 @@ type LoginUserInput struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required"`
 }
+
+type RefreshSessionInput struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// LogoutInput bersifat opsional; jika refresh_token dikirim, sesi refresh-nya
+// ikut dicabut.
+type LogoutInput struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type CreateUserInput struct {
+	Name     string `json:"name" binding:"required"`
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"omitempty,min=8"` // kosong = undangan dengan password sementara
+	Role     string `json:"role" binding:"required"`
+	TeamID   *int   `json:"team_id"`
+}
+
+type UpdateUserInput struct {
+	Name              string `json:"name"`
+	Email             string `json:"email" binding:"omitempty,email"`
+	Password          string `json:"password" binding:"omitempty,min=8"`
+	Role              string `json:"role"`
+	TeamID            *int   `json:"team_id"`
+	TwoFactorRequired *bool  `json:"two_factor_required"`
+}
+
+type ForgotPasswordInput struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordInput struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=8"`
+}
+
+// OIDCLoginInput berisi claim ID token yang sudah diverifikasi oleh package oidc.
+type OIDCLoginInput struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+type TwoFactorChallengeInput struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
+
+type TwoFactorEnrollInput struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+}
+
+type TwoFactorCodeInput struct {
+	Code string `json:"code" binding:"required"`
+}
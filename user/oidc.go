@@ -0,0 +1,88 @@
+package user
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"footballteam/helper"
+)
+
+var (
+	ErrOIDCEmailUnverified = errors.New("identity provider did not return a verified email")
+	ErrOIDCAccountConflict = errors.New("account is already linked to another identity")
+)
+
+const oidcPasswordSize = 32
+
+// LoginWithOIDC mencari user berdasarkan subject IdP. Pada login pertama,
+// akun lokal dengan email terverifikasi yang sama akan ditautkan; jika belum
+// ada, user baru dibuat dengan role OIDCDefaultRole.
+func (s *service) LoginWithOIDC(input OIDCLoginInput) (User, error) {
+	user, err := s.repository.FindByOIDCSubject(input.Subject)
+	if err != nil {
+		return user, err
+	}
+
+	if user.ID == 0 {
+		user, err = s.linkOIDCUser(input)
+		if err != nil {
+			return user, err
+		}
+	}
+
+	if !user.IsActive() {
+		return user, ErrUserDeactivated
+	}
+
+	return user, nil
+}
+
+func (s *service) linkOIDCUser(input OIDCLoginInput) (User, error) {
+	email := strings.ToLower(strings.TrimSpace(input.Email))
+	if email == "" || !input.EmailVerified {
+		return User{}, ErrOIDCEmailUnverified
+	}
+
+	subject := input.Subject
+
+	user, err := s.repository.FindByEmail(email)
+	if err != nil {
+		return user, err
+	}
+	if user.ID != 0 {
+		if user.OIDCSubject != nil {
+			return User{}, ErrOIDCAccountConflict
+		}
+
+		user.OIDCSubject = &subject
+		user.UpdatedAt = time.Now()
+		return s.repository.Update(user)
+	}
+
+	// Password acak yang tidak pernah diberikan ke siapa pun; user tetap bisa
+	// membuat password sendiri lewat lupa password
+	password, err := helper.GenerateRandomToken(oidcPasswordSize)
+	if err != nil {
+		return User{}, err
+	}
+	passwordHash, err := helper.HashPassword(password)
+	if err != nil {
+		return User{}, err
+	}
+
+	name := input.Name
+	if name == "" {
+		name = email
+	}
+
+	return s.repository.Save(User{
+		Name:         name,
+		Email:        email,
+		PasswordHash: passwordHash,
+		Role:         s.config.OIDCDefaultRole,
+		OIDCSubject:  &subject,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	})
+}
@@ -0,0 +1,141 @@
+package user
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeRepository menyimpan user di memori. Method yang tidak dipakai test ini
+// diteruskan ke Repository nil dan akan panic jika terpanggil.
+type fakeRepository struct {
+	Repository
+	users         []User
+	recoveryCodes []RecoveryCode
+}
+
+func (r *fakeRepository) FindByEmail(email string) (User, error) {
+	for _, u := range r.users {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return User{}, nil
+}
+
+func (r *fakeRepository) FindByOIDCSubject(subject string) (User, error) {
+	for _, u := range r.users {
+		if u.OIDCSubject != nil && *u.OIDCSubject == subject {
+			return u, nil
+		}
+	}
+	return User{}, nil
+}
+
+func (r *fakeRepository) Save(user User) (User, error) {
+	user.ID = len(r.users) + 1
+	r.users = append(r.users, user)
+	return user, nil
+}
+
+func (r *fakeRepository) Update(user User) (User, error) {
+	for i, u := range r.users {
+		if u.ID == user.ID {
+			r.users[i] = user
+		}
+	}
+	return user, nil
+}
+
+func oidcInput(subject string, email string, verified bool) OIDCLoginInput {
+	return OIDCLoginInput{Subject: subject, Email: email, EmailVerified: verified, Name: "Budi"}
+}
+
+func TestLoginWithOIDCCreatesUser(t *testing.T) {
+	repository := &fakeRepository{}
+	s := NewService(repository, nil, Config{})
+
+	user, err := s.LoginWithOIDC(oidcInput("subject-1", "Budi@Example.com", true))
+	if err != nil {
+		t.Fatalf("LoginWithOIDC: %v", err)
+	}
+	if len(repository.users) != 1 {
+		t.Fatalf("users = %d, want 1", len(repository.users))
+	}
+	if user.Email != "budi@example.com" || user.Role != RoleViewer || user.OIDCSubject == nil || *user.OIDCSubject != "subject-1" {
+		t.Errorf("created user = %+v", user)
+	}
+	if user.PasswordHash == "" {
+		t.Error("created user has no password hash")
+	}
+
+	// Login berikutnya memakai subject, bukan membuat user baru
+	again, err := s.LoginWithOIDC(oidcInput("subject-1", "budi@example.com", true))
+	if err != nil {
+		t.Fatalf("second LoginWithOIDC: %v", err)
+	}
+	if again.ID != user.ID || len(repository.users) != 1 {
+		t.Errorf("second login created another user: %+v", repository.users)
+	}
+}
+
+func TestLoginWithOIDCUsesDefaultRole(t *testing.T) {
+	repository := &fakeRepository{}
+	s := NewService(repository, nil, Config{OIDCDefaultRole: RoleEditor})
+
+	user, err := s.LoginWithOIDC(oidcInput("subject-1", "budi@example.com", true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Role != RoleEditor {
+		t.Errorf("role = %q, want %q", user.Role, RoleEditor)
+	}
+}
+
+func TestLoginWithOIDCLinksExistingUser(t *testing.T) {
+	repository := &fakeRepository{users: []User{{ID: 1, Email: "budi@example.com", Role: RoleAdmin, TOTPEnabled: true}}}
+	s := NewService(repository, nil, Config{})
+
+	user, err := s.LoginWithOIDC(oidcInput("subject-1", "budi@example.com", true))
+	if err != nil {
+		t.Fatalf("LoginWithOIDC: %v", err)
+	}
+	if user.ID != 1 || user.Role != RoleAdmin || !user.TOTPEnabled {
+		t.Errorf("linked user = %+v, want existing admin with 2FA", user)
+	}
+	if len(repository.users) != 1 || repository.users[0].OIDCSubject == nil || *repository.users[0].OIDCSubject != "subject-1" {
+		t.Errorf("subject was not stored: %+v", repository.users)
+	}
+}
+
+func TestLoginWithOIDCRejects(t *testing.T) {
+	linked := "other-subject"
+	deactivatedAt := time.Now()
+
+	tests := []struct {
+		name  string
+		users []User
+		input OIDCLoginInput
+		want  error
+	}{
+		{"unverified email", nil, oidcInput("subject-1", "budi@example.com", false), ErrOIDCEmailUnverified},
+		{"unverified email with existing account", []User{{ID: 1, Email: "budi@example.com"}}, oidcInput("subject-1", "budi@example.com", false), ErrOIDCEmailUnverified},
+		{"missing email", nil, oidcInput("subject-1", "", true), ErrOIDCEmailUnverified},
+		{"account linked to another subject", []User{{ID: 1, Email: "budi@example.com", OIDCSubject: &linked}}, oidcInput("subject-1", "budi@example.com", true), ErrOIDCAccountConflict},
+		{"deactivated account", []User{{ID: 1, Email: "budi@example.com", DeactivatedAt: &deactivatedAt}}, oidcInput("subject-1", "budi@example.com", true), ErrUserDeactivated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := &fakeRepository{users: tt.users}
+			s := NewService(repository, nil, Config{})
+
+			if _, err := s.LoginWithOIDC(tt.input); !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+			if len(repository.users) != len(tt.users) {
+				t.Errorf("users = %d, want %d", len(repository.users), len(tt.users))
+			}
+		})
+	}
+}
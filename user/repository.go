@@ -1,10 +1,26 @@
 package user
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Repository interface {
 	FindByEmail(email string) (User, error)
 	FindByID(ID int) (User, error)
+	FindByOIDCSubject(subject string) (User, error)
+	FindAll() ([]User, error)
+	Save(user User) (User, error)
+	Update(user User) (User, error)
+	UpdatePassword(user User, revokeTokens func(tx *gorm.DB) error) (User, error)
+	SaveResetToken(token PasswordResetToken) (PasswordResetToken, error)
+	FindResetTokenByHash(hash string) (PasswordResetToken, error)
+	UseResetToken(ID int, usedAt time.Time) (bool, error)
+	InvalidateResetTokens(userID int, usedAt time.Time) error
+	ReplaceRecoveryCodes(userID int, codes []RecoveryCode) error
+	FindRecoveryCode(userID int, hash string) (RecoveryCode, error)
+	UseRecoveryCode(ID int, usedAt time.Time) (bool, error)
 }
 
 type repository struct {
@@ -26,6 +42,17 @@ func (r *repository) FindByEmail(email string) (User, error) {
 	return user, nil
 }
 
+func (r *repository) FindByOIDCSubject(subject string) (User, error) {
+	var user User
+
+	err := r.db.Where("oidc_subject = ?", subject).Find(&user).Error
+	if err != nil {
+		return user, err
+	}
+
+	return user, nil
+}
+
 func (r *repository) FindByID(ID int) (User, error) {
 
 	var user User
@@ -35,3 +62,82 @@ func (r *repository) FindByID(ID int) (User, error) {
 	}
 	return user, nil
 }
+
+func (r *repository) FindAll() ([]User, error) {
+	var users []User
+	err := r.db.Order("id").Find(&users).Error
+	return users, err
+}
+
+func (r *repository) Save(user User) (User, error) {
+	err := r.db.Create(&user).Error
+	return user, err
+}
+
+func (r *repository) Update(user User) (User, error) {
+	err := r.db.Save(&user).Error
+	return user, err
+}
+
+// UpdatePassword menyimpan user dan menjalankan revokeTokens dalam satu
+// transaksi, sehingga password tidak berubah jika token lama gagal dicabut.
+func (r *repository) UpdatePassword(user User, revokeTokens func(tx *gorm.DB) error) (User, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&user).Error; err != nil {
+			return err
+		}
+		return revokeTokens(tx)
+	})
+	return user, err
+}
+
+func (r *repository) SaveResetToken(token PasswordResetToken) (PasswordResetToken, error) {
+	err := r.db.Create(&token).Error
+	return token, err
+}
+
+func (r *repository) FindResetTokenByHash(hash string) (PasswordResetToken, error) {
+	var token PasswordResetToken
+	err := r.db.Where("token_hash = ?", hash).First(&token).Error
+	return token, err
+}
+
+// UseResetToken menandai token terpakai hanya jika belum pernah dipakai,
+// sehingga token yang sama tidak bisa dipakai dua kali secara bersamaan.
+func (r *repository) UseResetToken(ID int, usedAt time.Time) (bool, error) {
+	result := r.db.Model(&PasswordResetToken{}).
+		Where("id = ? AND used_at IS NULL", ID).
+		Update("used_at", usedAt)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *repository) InvalidateResetTokens(userID int, usedAt time.Time) error {
+	return r.db.Model(&PasswordResetToken{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", usedAt).Error
+}
+
+func (r *repository) ReplaceRecoveryCodes(userID int, codes []RecoveryCode) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+func (r *repository) FindRecoveryCode(userID int, hash string) (RecoveryCode, error) {
+	var code RecoveryCode
+	err := r.db.Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).First(&code).Error
+	return code, err
+}
+
+func (r *repository) UseRecoveryCode(ID int, usedAt time.Time) (bool, error) {
+	result := r.db.Model(&RecoveryCode{}).
+		Where("id = ? AND used_at IS NULL", ID).
+		Update("used_at", usedAt)
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,121 @@
+package user
+
+import "errors"
+
+var ErrTeamAccessDenied = errors.New("you can only manage your own team")
+
+const (
+	RoleAdmin       = "admin"
+	RoleEditor      = "editor"
+	RoleScorekeeper = "scorekeeper"
+	RoleManager     = "manager"
+	RoleViewer      = "viewer"
+)
+
+type Permission string
+
+const (
+	PermissionWriteTeams         Permission = "teams:write"
+	PermissionDeleteTeams        Permission = "teams:delete"
+	PermissionUploadTeamLogo     Permission = "teams:logo"
+	PermissionWritePlayers       Permission = "players:write"
+	PermissionDeletePlayers      Permission = "players:delete"
+	PermissionWriteStaff         Permission = "staff:write"
+	PermissionDeleteStaff        Permission = "staff:delete"
+	PermissionWriteMatches       Permission = "matches:write"
+	PermissionDeleteMatches      Permission = "matches:delete"
+	PermissionWriteMatchResults  Permission = "match_results:write"
+	PermissionDeleteMatchResults Permission = "match_results:delete"
+	PermissionWriteVenues        Permission = "venues:write"
+	PermissionDeleteVenues       Permission = "venues:delete"
+	PermissionManageUsers        Permission = "users:manage"
+	PermissionManageAPIKeys      Permission = "api_keys:manage"
+	PermissionReadAudit          Permission = "audit:read"
+	PermissionPurgeTrash         Permission = "trash:purge"
+)
+
+// Viewer tidak punya permission tulis; semua endpoint GET memang publik.
+var rolePermissions = map[string][]Permission{
+	RoleAdmin: {
+		PermissionWriteTeams,
+		PermissionDeleteTeams,
+		PermissionUploadTeamLogo,
+		PermissionWritePlayers,
+		PermissionDeletePlayers,
+		PermissionWriteStaff,
+		PermissionDeleteStaff,
+		PermissionWriteMatches,
+		PermissionDeleteMatches,
+		PermissionWriteMatchResults,
+		PermissionDeleteMatchResults,
+		PermissionWriteVenues,
+		PermissionDeleteVenues,
+		PermissionManageUsers,
+		PermissionManageAPIKeys,
+		PermissionReadAudit,
+		PermissionPurgeTrash,
+	},
+	RoleEditor: {
+		PermissionWriteTeams,
+		PermissionUploadTeamLogo,
+		PermissionWritePlayers,
+		PermissionDeletePlayers,
+		PermissionWriteStaff,
+		PermissionDeleteStaff,
+		PermissionWriteMatches,
+		PermissionDeleteMatches,
+		PermissionWriteMatchResults,
+		PermissionDeleteMatchResults,
+		PermissionWriteVenues,
+		PermissionDeleteVenues,
+	},
+	RoleScorekeeper: {
+		PermissionWriteMatchResults,
+	},
+	// Manager hanya berlaku untuk klubnya sendiri, lihat CanManageTeam
+	RoleManager: {
+		PermissionUploadTeamLogo,
+		PermissionWritePlayers,
+		PermissionDeletePlayers,
+		PermissionWriteStaff,
+		PermissionDeleteStaff,
+	},
+	RoleViewer: {},
+}
+
+func IsValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+func (u User) Can(permission Permission) bool {
+	for _, p := range rolePermissions[u.Role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// CanManageTeam membatasi role yang terikat ke satu klub (manager) hanya ke
+// TeamID miliknya. Role lain tidak terikat ke klub, hak aksesnya cukup dicek
+// lewat Can di router.
+func (u User) CanManageTeam(teamID int) bool {
+	if u.Role != RoleManager {
+		return true
+	}
+	return u.TeamID != nil && *u.TeamID == teamID
+}
+
+// ManagedTeamIDs dipakai untuk memfilter daftar data per klub. restricted
+// bernilai false untuk role yang tidak terikat ke klub; manager tanpa TeamID
+// mendapat daftar kosong.
+func (u User) ManagedTeamIDs() (teamIDs []int, restricted bool) {
+	if u.Role != RoleManager {
+		return nil, false
+	}
+	if u.TeamID == nil {
+		return []int{}, true
+	}
+	return []int{*u.TeamID}, true
+}
@@ -2,25 +2,99 @@ package user
 
 import (
 	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
 
-	"golang.org/x/crypto/bcrypt"
+	"footballteam/helper"
+	"footballteam/mailer"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidCredentials   = errors.New("invalid email or password")
+	ErrUserDeactivated      = errors.New("this account has been deactivated")
+	ErrEmailAlreadyUsed     = errors.New("email is already used by another account")
+	ErrInvalidRole          = errors.New("invalid role")
+	ErrManagerTeamRequired  = errors.New("manager accounts must be bound to a team")
+	ErrCannotDeactivateSelf = errors.New("you cannot deactivate your own account")
+	ErrInvalidResetToken    = errors.New("reset token is invalid or has expired")
+)
+
+const (
+	DefaultPasswordResetTTL = time.Hour
+
+	temporaryPasswordSize = 12
+	resetTokenSize        = 32
 )
 
 type Service interface {
 	Login(input LoginUserInput) (User, error)
 	GetUserByID(ID int) (User, error)
+	GetUsers() ([]User, error)
+	CreateUser(input CreateUserInput) (User, string, error)
+	UpdateUser(ID int, input UpdateUserInput) (User, error)
+	DeactivateUser(ID int, currentUser User) (User, error)
+	ActivateUser(ID int) (User, error)
+	RequestPasswordReset(input ForgotPasswordInput) error
+	ResetPassword(input ResetPasswordInput) (User, error)
+	BeginTwoFactorEnrollment(ID int) (string, string, error)
+	ConfirmTwoFactorEnrollment(ID int, code string) ([]string, error)
+	VerifyTwoFactor(ID int, code string) (User, error)
+	DisableTwoFactor(ID int, code string) (User, error)
+	ResetTwoFactor(ID int) (User, error)
+	LoginWithOIDC(input OIDCLoginInput) (User, error)
+}
+
+type Config struct {
+	// PasswordResetURL adalah halaman front end yang menerima ?token=...
+	PasswordResetURL string
+	PasswordResetTTL time.Duration
+	TOTPIssuer       string
+	// OIDCDefaultRole diberikan ke user yang pertama kali login lewat OIDC
+	OIDCDefaultRole string
+	// TokenRevoker mencabut semua token user saat password berubah
+	TokenRevoker TokenRevoker
+}
+
+// TokenRevoker diimplementasikan oleh auth.Service. Pencabutan ditulis lewat
+// transaksi yang sama dengan perubahan password.
+type TokenRevoker interface {
+	RevokeUserTokensTx(tx *gorm.DB, userID int) error
 }
 
 type service struct {
 	repository Repository
+	mailer     mailer.Mailer
+	config     Config
 }
 
-func NewService(repository Repository) *service {
-	return &service{repository}
+func NewService(repository Repository, mailer mailer.Mailer, config Config) *service {
+	if config.PasswordResetTTL <= 0 {
+		config.PasswordResetTTL = DefaultPasswordResetTTL
+	}
+	if config.TOTPIssuer == "" {
+		config.TOTPIssuer = DefaultTOTPIssuer
+	}
+	if config.OIDCDefaultRole == "" {
+		config.OIDCDefaultRole = RoleViewer
+	}
+
+	return &service{repository, mailer, config}
 }
 
+// dummyPasswordHash dipakai saat email tidak ditemukan supaya waktu respon
+// login sama dengan saat password salah.
+var (
+	dummyPasswordHash     string
+	dummyPasswordHashOnce sync.Once
+)
+
 func (s *service) Login(input LoginUserInput) (User, error) {
-	email := input.Email
+	email := strings.ToLower(strings.TrimSpace(input.Email))
 	password := input.Password
 
 	user, err := s.repository.FindByEmail(email)
@@ -29,12 +103,20 @@ func (s *service) Login(input LoginUserInput) (User, error) {
 	}
 
 	if user.ID == 0 {
-		return user, errors.New("No user found on that email")
+		dummyPasswordHashOnce.Do(func() {
+			dummyPasswordHash, _ = helper.HashPassword("footballteam-dummy-password")
+		})
+		helper.CheckPassword(dummyPasswordHash, password)
+		return User{}, ErrInvalidCredentials
 	}
 
-	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password))
+	err = helper.CheckPassword(user.PasswordHash, password)
 	if err != nil {
-		return user, err
+		return User{}, ErrInvalidCredentials
+	}
+
+	if !user.IsActive() {
+		return user, ErrUserDeactivated
 	}
 
 	return user, nil
@@ -54,3 +136,251 @@ func (s *service) GetUserByID(ID int) (User, error) {
 	return user, nil
 }
 
+func (s *service) GetUsers() ([]User, error) {
+	return s.repository.FindAll()
+}
+
+// CreateUser mengembalikan password sementara jika input.Password kosong
+// (alur undangan). Password tersebut hanya ditampilkan sekali.
+func (s *service) CreateUser(input CreateUserInput) (User, string, error) {
+	if err := validateRole(input.Role, input.TeamID); err != nil {
+		return User{}, "", err
+	}
+
+	email := strings.ToLower(strings.TrimSpace(input.Email))
+	existing, err := s.repository.FindByEmail(email)
+	if err != nil {
+		return User{}, "", err
+	}
+	if existing.ID != 0 {
+		return User{}, "", ErrEmailAlreadyUsed
+	}
+
+	password := input.Password
+	temporaryPassword := ""
+	if password == "" {
+		temporaryPassword, err = helper.GenerateRandomToken(temporaryPasswordSize)
+		if err != nil {
+			return User{}, "", err
+		}
+		password = temporaryPassword
+	}
+
+	passwordHash, err := helper.HashPassword(password)
+	if err != nil {
+		return User{}, "", err
+	}
+
+	user := User{
+		Name:         input.Name,
+		Email:        email,
+		PasswordHash: passwordHash,
+		Role:         input.Role,
+		TeamID:       teamIDForRole(input.Role, input.TeamID),
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+
+	newUser, err := s.repository.Save(user)
+	if err != nil {
+		return newUser, "", err
+	}
+
+	return newUser, temporaryPassword, nil
+}
+
+func (s *service) UpdateUser(ID int, input UpdateUserInput) (User, error) {
+	user, err := s.GetUserByID(ID)
+	if err != nil {
+		return user, err
+	}
+
+	if input.Email != "" {
+		email := strings.ToLower(strings.TrimSpace(input.Email))
+		existing, err := s.repository.FindByEmail(email)
+		if err != nil {
+			return user, err
+		}
+		if existing.ID != 0 && existing.ID != user.ID {
+			return user, ErrEmailAlreadyUsed
+		}
+		user.Email = email
+	}
+
+	role := user.Role
+	if input.Role != "" {
+		role = input.Role
+	}
+	teamID := user.TeamID
+	if input.TeamID != nil {
+		teamID = input.TeamID
+	}
+	if err := validateRole(role, teamID); err != nil {
+		return user, err
+	}
+	user.Role = role
+	user.TeamID = teamIDForRole(role, teamID)
+
+	if input.Name != "" {
+		user.Name = input.Name
+	}
+	if input.TwoFactorRequired != nil {
+		user.TOTPRequired = *input.TwoFactorRequired
+	}
+	user.UpdatedAt = time.Now()
+
+	// Ganti password membatalkan semua sesi yang sudah ada
+	if input.Password != "" {
+		passwordHash, err := helper.HashPassword(input.Password)
+		if err != nil {
+			return user, err
+		}
+		user.PasswordHash = passwordHash
+		return s.updatePassword(user)
+	}
+
+	return s.repository.Update(user)
+}
+
+func (s *service) DeactivateUser(ID int, currentUser User) (User, error) {
+	if ID == currentUser.ID {
+		return currentUser, ErrCannotDeactivateSelf
+	}
+
+	user, err := s.GetUserByID(ID)
+	if err != nil {
+		return user, err
+	}
+
+	if user.DeactivatedAt == nil {
+		now := time.Now()
+		user.DeactivatedAt = &now
+		user.UpdatedAt = now
+	}
+
+	return s.repository.Update(user)
+}
+
+func (s *service) ActivateUser(ID int) (User, error) {
+	user, err := s.GetUserByID(ID)
+	if err != nil {
+		return user, err
+	}
+
+	user.DeactivatedAt = nil
+	user.UpdatedAt = time.Now()
+
+	return s.repository.Update(user)
+}
+
+// RequestPasswordReset tidak mengembalikan error jika email tidak terdaftar
+// agar endpoint tidak bisa dipakai untuk menebak email.
+func (s *service) RequestPasswordReset(input ForgotPasswordInput) error {
+	user, err := s.repository.FindByEmail(strings.ToLower(strings.TrimSpace(input.Email)))
+	if err != nil {
+		return err
+	}
+	if user.ID == 0 || !user.IsActive() {
+		return nil
+	}
+
+	now := time.Now()
+	if err := s.repository.InvalidateResetTokens(user.ID, now); err != nil {
+		return err
+	}
+
+	token, err := helper.GenerateRandomToken(resetTokenSize)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.repository.SaveResetToken(PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: helper.HashToken(token),
+		ExpiresAt: now.Add(s.config.PasswordResetTTL),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return err
+	}
+
+	link := token
+	if s.config.PasswordResetURL != "" {
+		link = s.config.PasswordResetURL + "?token=" + url.QueryEscape(token)
+	}
+
+	return s.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Reset password",
+		Body: fmt.Sprintf("Halo %s,\n\nGunakan tautan berikut untuk mengatur ulang password Anda:\n%s\n\nTautan ini berlaku selama %s dan hanya bisa dipakai sekali. Abaikan email ini jika Anda tidak meminta reset password.\n",
+			user.Name, link, s.config.PasswordResetTTL),
+	})
+}
+
+func (s *service) ResetPassword(input ResetPasswordInput) (User, error) {
+	token, err := s.repository.FindResetTokenByHash(helper.HashToken(input.Token))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return User{}, ErrInvalidResetToken
+	}
+	if err != nil {
+		return User{}, err
+	}
+
+	now := time.Now()
+	if token.UsedAt != nil || now.After(token.ExpiresAt) {
+		return User{}, ErrInvalidResetToken
+	}
+
+	user, err := s.GetUserByID(token.UserID)
+	if err != nil {
+		return user, err
+	}
+	if !user.IsActive() {
+		return user, ErrInvalidResetToken
+	}
+
+	used, err := s.repository.UseResetToken(token.ID, now)
+	if err != nil {
+		return user, err
+	}
+	if !used {
+		return user, ErrInvalidResetToken
+	}
+
+	passwordHash, err := helper.HashPassword(input.Password)
+	if err != nil {
+		return user, err
+	}
+	user.PasswordHash = passwordHash
+	user.UpdatedAt = now
+
+	return s.updatePassword(user)
+}
+
+// updatePassword menyimpan password baru sekaligus mencabut semua token user.
+func (s *service) updatePassword(user User) (User, error) {
+	return s.repository.UpdatePassword(user, func(tx *gorm.DB) error {
+		if s.config.TokenRevoker == nil {
+			return nil
+		}
+		return s.config.TokenRevoker.RevokeUserTokensTx(tx, user.ID)
+	})
+}
+
+func validateRole(role string, teamID *int) error {
+	if !IsValidRole(role) {
+		return ErrInvalidRole
+	}
+	if role == RoleManager && (teamID == nil || *teamID == 0) {
+		return ErrManagerTeamRequired
+	}
+	return nil
+}
+
+// Hanya manager yang terikat ke klub
+func teamIDForRole(role string, teamID *int) *int {
+	if role != RoleManager {
+		return nil
+	}
+	return teamID
+}
@@ -0,0 +1,75 @@
+package user
+
+import (
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func (r *fakeRepository) FindByID(ID int) (User, error) {
+	for _, u := range r.users {
+		if u.ID == ID {
+			return u, nil
+		}
+	}
+	return User{}, nil
+}
+
+// UpdatePassword meniru transaksi: user hanya tersimpan jika revokeTokens
+// berhasil.
+func (r *fakeRepository) UpdatePassword(user User, revokeTokens func(tx *gorm.DB) error) (User, error) {
+	if err := revokeTokens(nil); err != nil {
+		return user, err
+	}
+	return r.Update(user)
+}
+
+type fakeTokenRevoker struct {
+	err     error
+	revoked []int
+}
+
+func (r *fakeTokenRevoker) RevokeUserTokensTx(tx *gorm.DB, userID int) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.revoked = append(r.revoked, userID)
+	return nil
+}
+
+func TestUpdateUserPasswordRevokesTokens(t *testing.T) {
+	revocationErr := errors.New("revocation failed")
+
+	tests := []struct {
+		name        string
+		input       UpdateUserInput
+		revokerErr  error
+		wantErr     error
+		wantRevoked []int
+		wantChanged bool
+	}{
+		{"password change", UpdateUserInput{Password: "password-baru-123"}, nil, nil, []int{1}, true},
+		{"revocation fails", UpdateUserInput{Password: "password-baru-123"}, revocationErr, revocationErr, nil, false},
+		{"no password change", UpdateUserInput{Name: "Budi Baru"}, nil, nil, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := &fakeRepository{users: []User{{ID: 1, Name: "Budi", Email: "budi@example.com", Role: RoleAdmin, PasswordHash: "old-hash"}}}
+			revoker := &fakeTokenRevoker{err: tt.revokerErr}
+			s := NewService(repository, nil, Config{TokenRevoker: revoker})
+
+			_, err := s.UpdateUser(1, tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UpdateUser error = %v, want %v", err, tt.wantErr)
+			}
+			if len(revoker.revoked) != len(tt.wantRevoked) {
+				t.Errorf("revoked = %v, want %v", revoker.revoked, tt.wantRevoked)
+			}
+			if changed := repository.users[0].PasswordHash != "old-hash"; changed != tt.wantChanged {
+				t.Errorf("password changed = %v, want %v", changed, tt.wantChanged)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package user
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"footballteam/helper"
+	"footballteam/totp"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnrolled    = errors.New("two-factor enrollment has not been started")
+	ErrTwoFactorNotEnabled     = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorRequired       = errors.New("two-factor authentication is required for this account")
+	ErrInvalidTwoFactorCode    = errors.New("invalid two-factor code")
+)
+
+const (
+	DefaultTOTPIssuer = "Football Team"
+	recoveryCodeCount = 10
+	recoveryCodeSize  = 5
+)
+
+// BeginTwoFactorEnrollment membuat secret baru. 2FA baru aktif setelah
+// ConfirmTwoFactorEnrollment menerima kode yang valid dari secret ini.
+func (s *service) BeginTwoFactorEnrollment(ID int) (string, string, error) {
+	user, err := s.GetUserByID(ID)
+	if err != nil {
+		return "", "", err
+	}
+	if user.TOTPEnabled {
+		return "", "", ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return "", "", err
+	}
+
+	user.TOTPSecret = secret
+	user.TOTPLastCounter = 0
+	user.UpdatedAt = time.Now()
+	if _, err := s.repository.Update(user); err != nil {
+		return "", "", err
+	}
+
+	return secret, totp.ProvisioningURI(s.config.TOTPIssuer, user.Email, secret), nil
+}
+
+// ConfirmTwoFactorEnrollment mengaktifkan 2FA dan mengembalikan recovery
+// code yang hanya ditampilkan sekali.
+func (s *service) ConfirmTwoFactorEnrollment(ID int, code string) ([]string, error) {
+	user, err := s.GetUserByID(ID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, ErrTwoFactorNotEnrolled
+	}
+
+	counter, ok := totp.Validate(user.TOTPSecret, code, time.Now(), user.TOTPLastCounter)
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	codes, records, err := generateRecoveryCodes(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repository.ReplaceRecoveryCodes(user.ID, records); err != nil {
+		return nil, err
+	}
+
+	user.TOTPEnabled = true
+	user.TOTPLastCounter = counter
+	user.UpdatedAt = time.Now()
+	if _, err := s.repository.Update(user); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// VerifyTwoFactor menerima kode TOTP 6 digit atau salah satu recovery code.
+func (s *service) VerifyTwoFactor(ID int, code string) (User, error) {
+	user, err := s.GetUserByID(ID)
+	if err != nil {
+		return user, err
+	}
+	if !user.TOTPEnabled {
+		return user, ErrTwoFactorNotEnabled
+	}
+	if !user.IsActive() {
+		return user, ErrUserDeactivated
+	}
+
+	if counter, ok := totp.Validate(user.TOTPSecret, code, time.Now(), user.TOTPLastCounter); ok {
+		user.TOTPLastCounter = counter
+		return s.repository.Update(user)
+	}
+
+	recovery, err := s.repository.FindRecoveryCode(user.ID, helper.HashToken(normalizeRecoveryCode(code)))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return user, ErrInvalidTwoFactorCode
+	}
+	if err != nil {
+		return user, err
+	}
+
+	used, err := s.repository.UseRecoveryCode(recovery.ID, time.Now())
+	if err != nil {
+		return user, err
+	}
+	if !used {
+		return user, ErrInvalidTwoFactorCode
+	}
+
+	return user, nil
+}
+
+// DisableTwoFactor dipakai user sendiri dan butuh kode yang valid.
+func (s *service) DisableTwoFactor(ID int, code string) (User, error) {
+	user, err := s.VerifyTwoFactor(ID, code)
+	if err != nil {
+		return user, err
+	}
+	if user.TOTPRequired {
+		return user, ErrTwoFactorRequired
+	}
+
+	return s.ResetTwoFactor(ID)
+}
+
+// ResetTwoFactor dipakai admin jika user kehilangan perangkatnya.
+func (s *service) ResetTwoFactor(ID int) (User, error) {
+	user, err := s.GetUserByID(ID)
+	if err != nil {
+		return user, err
+	}
+
+	if err := s.repository.ReplaceRecoveryCodes(user.ID, nil); err != nil {
+		return user, err
+	}
+
+	user.TOTPSecret = ""
+	user.TOTPEnabled = false
+	user.TOTPLastCounter = 0
+	user.UpdatedAt = time.Now()
+
+	return s.repository.Update(user)
+}
+
+func generateRecoveryCodes(userID int) ([]string, []RecoveryCode, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	records := make([]RecoveryCode, 0, recoveryCodeCount)
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		b := make([]byte, recoveryCodeSize)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+
+		raw := hex.EncodeToString(b)
+		code := raw[:5] + "-" + raw[5:]
+		codes = append(codes, code)
+		records = append(records, RecoveryCode{
+			UserID:    userID,
+			CodeHash:  helper.HashToken(normalizeRecoveryCode(code)),
+			CreatedAt: time.Now(),
+		})
+	}
+
+	return codes, records, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
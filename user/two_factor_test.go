@@ -0,0 +1,118 @@
+package user
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"footballteam/totp"
+
+	"gorm.io/gorm"
+)
+
+func (r *fakeRepository) ReplaceRecoveryCodes(userID int, codes []RecoveryCode) error {
+	r.recoveryCodes = nil
+	for i, code := range codes {
+		code.ID = i + 1
+		r.recoveryCodes = append(r.recoveryCodes, code)
+	}
+	return nil
+}
+
+func (r *fakeRepository) FindRecoveryCode(userID int, hash string) (RecoveryCode, error) {
+	for _, code := range r.recoveryCodes {
+		if code.UserID == userID && code.CodeHash == hash && code.UsedAt == nil {
+			return code, nil
+		}
+	}
+	return RecoveryCode{}, gorm.ErrRecordNotFound
+}
+
+func (r *fakeRepository) UseRecoveryCode(ID int, usedAt time.Time) (bool, error) {
+	for i, code := range r.recoveryCodes {
+		if code.ID == ID && code.UsedAt == nil {
+			r.recoveryCodes[i].UsedAt = &usedAt
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func twoFactorUser(t *testing.T) (*fakeRepository, []string) {
+	t.Helper()
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	repository := &fakeRepository{users: []User{{ID: 1, Email: "budi@example.com", Role: RoleAdmin, TOTPEnabled: true, TOTPSecret: secret}}}
+
+	codes, records, err := generateRecoveryCodes(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repository.ReplaceRecoveryCodes(1, records); err != nil {
+		t.Fatal(err)
+	}
+	return repository, codes
+}
+
+func TestVerifyTwoFactorRecoveryCodesWorkOnce(t *testing.T) {
+	repository, codes := twoFactorUser(t)
+	s := NewService(repository, nil, Config{})
+
+	if len(codes) != recoveryCodeCount {
+		t.Fatalf("got %d recovery codes, want %d", len(codes), recoveryCodeCount)
+	}
+
+	for _, code := range codes {
+		if _, err := s.VerifyTwoFactor(1, code); err != nil {
+			t.Fatalf("first use of %s: %v", code, err)
+		}
+		if _, err := s.VerifyTwoFactor(1, code); !errors.Is(err, ErrInvalidTwoFactorCode) {
+			t.Fatalf("second use of %s: err = %v, want ErrInvalidTwoFactorCode", code, err)
+		}
+	}
+
+	for _, code := range repository.recoveryCodes {
+		if code.UsedAt == nil {
+			t.Errorf("recovery code %d not marked as used", code.ID)
+		}
+	}
+}
+
+// Recovery code boleh diketik tanpa tanda hubung atau dengan huruf besar,
+// tetapi bentuk apa pun tetap hanya bisa dipakai sekali.
+func TestVerifyTwoFactorRecoveryCodeNormalized(t *testing.T) {
+	repository, codes := twoFactorUser(t)
+	s := NewService(repository, nil, Config{})
+
+	code := codes[0]
+	if _, err := s.VerifyTwoFactor(1, " "+strings.ToUpper(strings.ReplaceAll(code, "-", ""))+" "); err != nil {
+		t.Fatalf("normalized first use: %v", err)
+	}
+	if _, err := s.VerifyTwoFactor(1, code); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Errorf("reuse in original form: err = %v, want ErrInvalidTwoFactorCode", err)
+	}
+	if _, err := s.VerifyTwoFactor(1, "00000-00000"); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Errorf("unknown code: err = %v, want ErrInvalidTwoFactorCode", err)
+	}
+}
+
+func TestVerifyTwoFactorTOTPCannotBeReused(t *testing.T) {
+	repository, _ := twoFactorUser(t)
+	s := NewService(repository, nil, Config{})
+
+	code, err := totp.CodeAt(repository.users[0].TOTPSecret, totp.Counter(time.Now()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.VerifyTwoFactor(1, code); err != nil {
+		t.Fatalf("first use: %v", err)
+	}
+	if _, err := s.VerifyTwoFactor(1, code); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Errorf("second use: err = %v, want ErrInvalidTwoFactorCode", err)
+	}
+}
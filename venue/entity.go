@@ -0,0 +1,24 @@
+package venue
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	SurfaceGrass      = "grass"
+	SurfaceArtificial = "artificial"
+	SurfaceHybrid     = "hybrid"
+)
+
+type Venue struct {
+	ID        int    `gorm:"primaryKey"`
+	Name      string `gorm:"size:100;not null"`
+	City      string `gorm:"size:100"`
+	Capacity  int    `gorm:"not null;default:0"`
+	Surface   string `gorm:"size:20;not null"` // grass, artificial, hybrid
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
@@ -0,0 +1,27 @@
+package venue
+
+type VenueFormatter struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	City     string `json:"city"`
+	Capacity int    `json:"capacity"`
+	Surface  string `json:"surface"`
+}
+
+func FormatVenue(venue Venue) VenueFormatter {
+	return VenueFormatter{
+		ID:       venue.ID,
+		Name:     venue.Name,
+		City:     venue.City,
+		Capacity: venue.Capacity,
+		Surface:  venue.Surface,
+	}
+}
+
+func FormatVenues(venues []Venue) []VenueFormatter {
+	formatted := []VenueFormatter{}
+	for _, v := range venues {
+		formatted = append(formatted, FormatVenue(v))
+	}
+	return formatted
+}
@@ -0,0 +1,20 @@
+package venue
+
+import "footballteam/audit"
+
+type CreateVenueInput struct {
+	Name     string      `json:"name" binding:"required,max=100"`
+	City     string      `json:"city" binding:"max=100"`
+	Capacity int         `json:"capacity" binding:"min=0"`
+	Surface  string      `json:"surface" binding:"required,oneof=grass artificial hybrid"`
+	Actor    audit.Actor `json:"-"`
+}
+
+// UpdateVenueInput hanya mengubah field yang dikirim.
+type UpdateVenueInput struct {
+	Name     string      `json:"name" binding:"max=100"`
+	City     string      `json:"city" binding:"max=100"`
+	Capacity *int        `json:"capacity" binding:"omitempty,min=0"`
+	Surface  string      `json:"surface" binding:"omitempty,oneof=grass artificial hybrid"`
+	Actor    audit.Actor `json:"-"`
+}
@@ -0,0 +1,66 @@
+package venue
+
+import "gorm.io/gorm"
+
+type Repository interface {
+	FindAll() ([]Venue, error)
+	FindByID(id int) (Venue, error)
+	Create(venue Venue) (Venue, error)
+	Update(venue Venue) (Venue, error)
+	Delete(venue Venue) error
+	FindReferences(venueID int) (map[string][]int, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *repository {
+	return &repository{db}
+}
+
+func (r *repository) FindAll() ([]Venue, error) {
+	var venues []Venue
+	err := r.db.Order("name").Find(&venues).Error
+	return venues, err
+}
+
+func (r *repository) FindByID(id int) (Venue, error) {
+	var venue Venue
+	err := r.db.First(&venue, id).Error
+	return venue, err
+}
+
+func (r *repository) Create(venue Venue) (Venue, error) {
+	err := r.db.Create(&venue).Error
+	return venue, err
+}
+
+func (r *repository) Update(venue Venue) (Venue, error) {
+	err := r.db.Save(&venue).Error
+	return venue, err
+}
+
+func (r *repository) Delete(venue Venue) error {
+	return r.db.Delete(&venue).Error
+}
+
+// FindReferences memakai nama tabel langsung karena package team dan match
+// bergantung ke package venue.
+func (r *repository) FindReferences(venueID int) (map[string][]int, error) {
+	references := map[string][]int{}
+
+	for kind, column := range map[string]string{"teams": "home_venue_id", "matches": "venue_id"} {
+		var ids []int
+		err := r.db.Table(kind).
+			Where(column+" = ? AND deleted_at IS NULL", venueID).
+			Order("id").
+			Pluck("id", &ids).Error
+		if err != nil {
+			return nil, err
+		}
+		references[kind] = ids
+	}
+
+	return references, nil
+}
@@ -0,0 +1,111 @@
+package venue
+
+import (
+	"time"
+
+	"footballteam/audit"
+	"footballteam/helper"
+)
+
+const auditEntityType = "venue"
+
+type Service interface {
+	GetVenues() ([]Venue, error)
+	GetVenueByID(id int) (Venue, error)
+	CreateVenue(input CreateVenueInput) (Venue, error)
+	UpdateVenue(id int, input UpdateVenueInput) (Venue, error)
+	DeleteVenue(id int, actor audit.Actor) error
+}
+
+type service struct {
+	repository   Repository
+	auditService audit.Service
+}
+
+func NewService(repository Repository, auditService audit.Service) *service {
+	return &service{repository, auditService}
+}
+
+func (s *service) GetVenues() ([]Venue, error) {
+	return s.repository.FindAll()
+}
+
+func (s *service) GetVenueByID(id int) (Venue, error) {
+	return s.repository.FindByID(id)
+}
+
+func (s *service) CreateVenue(input CreateVenueInput) (Venue, error) {
+	venue := Venue{
+		Name:      input.Name,
+		City:      input.City,
+		Capacity:  input.Capacity,
+		Surface:   input.Surface,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	newVenue, err := s.repository.Create(venue)
+	if err != nil {
+		return newVenue, err
+	}
+
+	s.auditService.Record(input.Actor, audit.ActionCreate, auditEntityType, newVenue.ID, nil, newVenue)
+
+	return newVenue, nil
+}
+
+func (s *service) UpdateVenue(id int, input UpdateVenueInput) (Venue, error) {
+	venue, err := s.repository.FindByID(id)
+	if err != nil {
+		return venue, err
+	}
+	before := venue
+
+	if input.Name != "" {
+		venue.Name = input.Name
+	}
+	if input.City != "" {
+		venue.City = input.City
+	}
+	if input.Capacity != nil {
+		venue.Capacity = *input.Capacity
+	}
+	if input.Surface != "" {
+		venue.Surface = input.Surface
+	}
+	venue.UpdatedAt = time.Now()
+
+	updated, err := s.repository.Update(venue)
+	if err != nil {
+		return updated, err
+	}
+
+	s.auditService.Record(input.Actor, audit.ActionUpdate, auditEntityType, updated.ID, before, updated)
+
+	return updated, nil
+}
+
+// DeleteVenue ditolak dengan *helper.InUseError selama venue masih menjadi
+// kandang tim atau tempat pertandingan aktif.
+func (s *service) DeleteVenue(id int, actor audit.Actor) error {
+	venue, err := s.repository.FindByID(id)
+	if err != nil {
+		return err
+	}
+
+	references, err := s.repository.FindReferences(venue.ID)
+	if err != nil {
+		return err
+	}
+	if err := helper.NewInUseError(references); err != nil {
+		return err
+	}
+
+	if err := s.repository.Delete(venue); err != nil {
+		return err
+	}
+
+	s.auditService.Record(actor, audit.ActionDelete, auditEntityType, venue.ID, venue, nil)
+
+	return nil
+}